@@ -2,6 +2,7 @@ package main
 
 import (
 	"math/rand"
+	"rng"
 	"sort"
 )
 
@@ -69,35 +70,44 @@ func BetaDiversityMatrix(allMaps map[string](map[string]int), distMetric string)
 	return sampleNames, mtx
 }
 
-// DownSampleMaps takes a map of frequency maps and a threshold, and returns a map of frequency maps with the same keys, but with each frequency map randomly downsampled to the threshold.
-func DownSampleMaps(allMaps map[string]map[string]int, threshold int) map[string]map[string]int {
+// DownSampleMaps takes a map of frequency maps, a threshold, and a seed, and returns a map of frequency maps with the same keys, but with each frequency map randomly downsampled to the threshold.
+// Each sample is drawn from its own random stream, derived from the seed and the sample's name, so the result doesn't depend on the order in which the map is visited.
+func DownSampleMaps(allMaps map[string]map[string]int, threshold int, seed int64) map[string]map[string]int {
 	newMaps := make(map[string]map[string]int)
 
 	for key, freqMap := range allMaps {
-		newMap := DownSample(freqMap, threshold)
+		newMap := DownSample(freqMap, threshold, rng.New(rng.DeriveSeedFromKey(seed, key)))
 		newMaps[key] = newMap
 	}
 
 	return newMaps
 }
 
-// DownSample takes as input a frequency map and a threshold, and returns a new frequency map with the same keys, but with each value randomly downsampled to the threshold.
-func DownSample(freqMap map[string]int, threshold int) map[string]int {
+// DownSample takes as input a frequency map, a threshold, and a random stream, and returns a new frequency map with the same keys, but with each value randomly downsampled to the threshold.
+func DownSample(freqMap map[string]int, threshold int, source *rand.Rand) map[string]int {
 	total := SampleTotal(freqMap)
 	if total < threshold {
 		panic("DownSample() called on a frequency map with a total value less than the threshold!")
 	}
 
+	// Sort the keys, since ranging over a map visits them in a different order every time
+	keys := make([]string, 0, len(freqMap))
+	for key := range freqMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	// Create a slice to store all the keys, repeated according to their frequency
 	allKeys := make([]string, 0, total)
-	for key, count := range freqMap {
+	for _, key := range keys {
+		count := freqMap[key]
 		for i := 0; i < count; i++ {
 			allKeys = append(allKeys, key)
 		}
 	}
 
 	// Get a random permutation of indices
-	perm := rand.Perm(total)
+	perm := source.Perm(total)
 
 	// Create a new map to store the downsampled results
 	newMap := make(map[string]int)
@@ -240,3 +240,25 @@ func ReadSampleTotalTests(directory string) []SampleTotalTest {
 
 	return tests
 }
+
+// TestDownSampleMaps tests that DownSampleMaps downsamples every sample to the threshold and gives the same result for the same seed
+func TestDownSampleMaps(t *testing.T) {
+	allMaps := map[string]map[string]int{
+		"A": {"x": 50, "y": 30, "z": 20},
+		"B": {"x": 5, "y": 95},
+	}
+
+	first := DownSampleMaps(allMaps, 40, 1)
+	second := DownSampleMaps(allMaps, 40, 1)
+
+	for name := range allMaps {
+		if SampleTotal(first[name]) != 40 {
+			t.Errorf("DownSampleMaps() gave sample %s a total of %d, want 40", name, SampleTotal(first[name]))
+		}
+		for key, count := range first[name] {
+			if second[name][key] != count {
+				t.Errorf("DownSampleMaps() with the same seed gave different counts of %s in sample %s", key, name)
+			}
+		}
+	}
+}
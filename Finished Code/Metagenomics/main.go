@@ -2,11 +2,24 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"rng"
 )
 
 func main() {
 	fmt.Println("Metagenomics!")
 
+	// an optional seed on the command line repeats an earlier run's downsampling; otherwise the seed comes from the clock
+	seedString := "time"
+	if len(os.Args) > 1 {
+		seedString = os.Args[1]
+	}
+	seed, err := rng.ParseSeed(seedString)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Seed:", seed)
+
 	// step 1: reading input from a single file.
 
 	filename := "Data/Fall_Allegheny_1.txt"
@@ -41,7 +54,7 @@ func main() {
 
 	fmt.Println("Downsampling all samples to a threshold of", sequencingDepth)
 
-	downsampledMaps := DownSampleMaps(allMaps, sequencingDepth)
+	downsampledMaps := DownSampleMaps(allMaps, sequencingDepth, seed)
 
 	// step 3: processing the data that we have received.
 
@@ -0,0 +1,202 @@
+package main
+
+//this file contains factorials, permutations and binomial coefficients, both as ints that report overflow
+//and as big integers of any size computed with parallel product trees.
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"workpool"
+)
+
+// ErrOverflow is returned, wrapped with the details, when a result doesn't fit in an int.
+var ErrOverflow = errors.New("integer overflow")
+
+// MultiplyInts takes two integers and returns their product, or an error wrapping ErrOverflow if the product doesn't fit in an int.
+func MultiplyInts(a, b int) (int, error) {
+	p := a * b
+	if a != 0 && (p/a != b || (a == -1 && b == math.MinInt)) {
+		return 0, fmt.Errorf("%d * %d: %w", a, b, ErrOverflow)
+	}
+	return p, nil
+}
+
+// Factorial takes an integer n and returns n!, or an error if n is negative or n! doesn't fit in an int,
+// which happens beyond 20! for 64-bit ints.
+func Factorial(n int) (int, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("factorial of negative number %d", n)
+	}
+
+	prod, err := Perm(2, n+1)
+	if err != nil {
+		return 0, fmt.Errorf("%d!: %w", n, ErrOverflow)
+	}
+	return prod, nil
+}
+
+// Perm computes n permuted with k from the prep materials, as the product k * (k + 1) * ... * (n - 1) of the integers
+// from k up to but not including n, so that Perm(n-k+1, n+1) is the number of ways to arrange k of n objects.
+// It returns an error if the product doesn't fit in an int.
+func Perm(k, n int) (int, error) {
+	p := 1
+
+	for i := k; i < n; i++ {
+		var err error
+		p, err = MultiplyInts(p, i)
+		if err != nil {
+			return 0, fmt.Errorf("product of %d up to %d: %w", k, n, ErrOverflow)
+		}
+	}
+
+	return p, nil
+}
+
+// FactorialMultiProc takes integers n and numProcs, and returns n! computed by splitting the product over numProcs workers,
+// each multiplying one chunk of the integers from 1 to n with Perm. It returns an error if n is negative or if a chunk,
+// or the product of the chunks, doesn't fit in an int.
+func FactorialMultiProc(n, numProcs int) (int, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("factorial of negative number %d", n)
+	}
+
+	type partial struct {
+		prod int
+		err  error
+	}
+
+	// the factors run from 1 to n, so worker i multiplies the factors of its chunk, shifted up by 1
+	partials := make([]partial, workpool.Workers(n, numProcs))
+	workpool.For(n, numProcs, func(worker, start, end int) {
+		partials[worker].prod, partials[worker].err = Perm(start+1, end+1)
+	})
+
+	prod := 1
+	for _, part := range partials {
+		if part.err != nil {
+			return 0, fmt.Errorf("%d!: %w", n, part.err)
+		}
+		var err error
+		prod, err = MultiplyInts(prod, part.prod)
+		if err != nil {
+			return 0, fmt.Errorf("%d!: %w", n, err)
+		}
+	}
+
+	return prod, nil
+}
+
+// Binomial takes integers n and k, and returns the binomial coefficient n choose k, the number of ways to choose
+// k of n objects, which is 0 if k < 0 or k > n. It returns an error if n is negative or the result doesn't fit in an int,
+// even though the intermediate products may be far larger than the result.
+func Binomial(n, k int) (int, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("binomial coefficient of negative number %d", n)
+	}
+
+	c := BigBinomial(n, k, 1)
+	if !c.IsInt64() || c.Int64() > math.MaxInt {
+		return 0, fmt.Errorf("%d choose %d: %w", n, k, ErrOverflow)
+	}
+	return int(c.Int64()), nil
+}
+
+// leafSize is the number of factors that ProductTree multiplies directly rather than splitting further.
+const leafSize = 32
+
+// ProductTree takes integers k and n and returns the product of the integers from k up to but not including n,
+// of any size, or 1 if there are none. It splits the range in half, multiplies each half recursively, and multiplies
+// the two halves. Products of numbers of similar size are much faster to compute than multiplying one factor
+// at a time into an ever larger product, as big.Int uses faster algorithms for large operands of similar size.
+func ProductTree(k, n int) *big.Int {
+	if n-k <= leafSize {
+		p := big.NewInt(1)
+		factor := new(big.Int)
+		for i := k; i < n; i++ {
+			p.Mul(p, factor.SetInt64(int64(i)))
+		}
+		return p
+	}
+
+	mid := k + (n-k)/2
+	left := ProductTree(k, mid)
+	return left.Mul(left, ProductTree(mid, n))
+}
+
+// MultiplyAll takes a slice of big integers and a number of processors, and returns their product. The integers are
+// multiplied in pairs level by level as a tree, with the multiplications of each level divided over numProcs workers.
+// The slice is not changed.
+func MultiplyAll(factors []*big.Int, numProcs int) *big.Int {
+	if len(factors) == 0 {
+		return big.NewInt(1)
+	}
+
+	level := factors
+	for len(level) > 1 {
+		// pair up neighbors, leaving the last one on its own if there is an odd number
+		pairs := make([][]*big.Int, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 < len(level) {
+				pairs = append(pairs, level[i:i+2])
+			} else {
+				pairs = append(pairs, level[i:i+1])
+			}
+		}
+
+		level = workpool.Map(pairs, numProcs, func(pair []*big.Int) *big.Int {
+			if len(pair) == 1 {
+				return pair[0]
+			}
+			return new(big.Int).Mul(pair[0], pair[1])
+		})
+	}
+
+	return new(big.Int).Set(level[0])
+}
+
+// BigPerm takes integers k, n and numProcs, and returns the product of the integers from k up to but not including n
+// as Perm does, but of any size. The range is split into numProcs chunks, each multiplied by its own worker with
+// ProductTree, and the products of the chunks are then multiplied together with MultiplyAll.
+func BigPerm(k, n, numProcs int) *big.Int {
+	if n <= k {
+		return big.NewInt(1)
+	}
+
+	chunks := make([]*big.Int, workpool.Workers(n-k, numProcs))
+	workpool.For(n-k, numProcs, func(worker, start, end int) {
+		chunks[worker] = ProductTree(k+start, k+end)
+	})
+
+	return MultiplyAll(chunks, numProcs)
+}
+
+// BigFactorial takes integers n and numProcs, and returns n! of any size, computed over numProcs workers.
+// It panics if n is negative.
+func BigFactorial(n, numProcs int) *big.Int {
+	if n < 0 {
+		panic("Error: factorial of a negative number.")
+	}
+	return BigPerm(1, n+1, numProcs)
+}
+
+// BigBinomial takes integers n, k and numProcs, and returns n choose k of any size, which is 0 if k < 0 or k > n.
+// It divides the product of the k largest factors of n! by k!, both computed over numProcs workers.
+// It panics if n is negative.
+func BigBinomial(n, k, numProcs int) *big.Int {
+	if n < 0 {
+		panic("Error: binomial coefficient of a negative number.")
+	}
+	if k < 0 || k > n {
+		return big.NewInt(0)
+	}
+
+	// n choose k equals n choose n - k, so use whichever needs fewer factors
+	if k > n-k {
+		k = n - k
+	}
+
+	numerator := BigPerm(n-k+1, n+1, numProcs)
+	return numerator.Quo(numerator, BigFactorial(k, numProcs))
+}
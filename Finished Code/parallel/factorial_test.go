@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestFactorial tests that Factorial returns 20!, the largest factorial that fits in an int, and overflows at 21!.
+func TestFactorial(t *testing.T) {
+	if got, err := Factorial(20); err != nil || got != 2432902008176640000 {
+		t.Errorf("Factorial(20) = %d, %v; want 2432902008176640000", got, err)
+	}
+	if got, err := Factorial(21); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Factorial(21) = %d, %v; want an error wrapping ErrOverflow", got, err)
+	}
+	if got, err := Factorial(0); err != nil || got != 1 {
+		t.Errorf("Factorial(0) = %d, %v; want 1", got, err)
+	}
+}
+
+// TestFactorialMultiProc tests that FactorialMultiProc agrees with Factorial up to 20! and overflows at 21!,
+// for numbers of processors that divide the factors evenly, unevenly, and outnumber them.
+func TestFactorialMultiProc(t *testing.T) {
+	for _, numProcs := range []int{1, 2, 3, 7, 32} {
+		for n := 0; n <= 20; n++ {
+			want, _ := Factorial(n)
+			if got, err := FactorialMultiProc(n, numProcs); err != nil || got != want {
+				t.Errorf("FactorialMultiProc(%d, %d) = %d, %v; want %d", n, numProcs, got, err, want)
+			}
+		}
+		if got, err := FactorialMultiProc(21, numProcs); !errors.Is(err, ErrOverflow) {
+			t.Errorf("FactorialMultiProc(21, %d) = %d, %v; want an error wrapping ErrOverflow", numProcs, got, err)
+		}
+	}
+}
+
+// TestBinomial tests that Binomial returns 66 choose 33, which fits in an int although 66! doesn't,
+// and overflows at 68 choose 34.
+func TestBinomial(t *testing.T) {
+	if got, err := Binomial(66, 33); err != nil || got != 7219428434016265740 {
+		t.Errorf("Binomial(66, 33) = %d, %v; want 7219428434016265740", got, err)
+	}
+	if got, err := Binomial(68, 34); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Binomial(68, 34) = %d, %v; want an error wrapping ErrOverflow", got, err)
+	}
+	if got, err := Binomial(5, 7); err != nil || got != 0 {
+		t.Errorf("Binomial(5, 7) = %d, %v; want 0", got, err)
+	}
+}
+
+// TestBigFactorial tests BigFactorial against the product computed by math/big for several numbers of processors.
+func TestBigFactorial(t *testing.T) {
+	for _, n := range []int{0, 1, 20, 21, 100, 1000} {
+		want := new(big.Int).MulRange(1, int64(n))
+		for _, numProcs := range []int{1, 3, 8} {
+			if got := BigFactorial(n, numProcs); got.Cmp(want) != 0 {
+				t.Errorf("BigFactorial(%d, %d) = %s, want %s", n, numProcs, got, want)
+			}
+		}
+	}
+}
+
+// TestBigBinomial tests BigBinomial against the binomial coefficient computed by math/big, including the
+// coefficients too large for Binomial.
+func TestBigBinomial(t *testing.T) {
+	cases := [][2]int{{0, 0}, {10, 3}, {66, 33}, {68, 34}, {100, 50}, {1000, 999}}
+	for _, c := range cases {
+		want := new(big.Int).Binomial(int64(c[0]), int64(c[1]))
+		for _, numProcs := range []int{1, 3, 8} {
+			if got := BigBinomial(c[0], c[1], numProcs); got.Cmp(want) != 0 {
+				t.Errorf("BigBinomial(%d, %d, %d) = %s, want %s", c[0], c[1], numProcs, got, want)
+			}
+		}
+	}
+	if got := BigBinomial(5, 7, 2); got.Sign() != 0 {
+		t.Errorf("BigBinomial(5, 7, 2) = %s, want 0", got)
+	}
+}
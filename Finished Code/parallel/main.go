@@ -5,6 +5,7 @@ import (
 	"log"
 	"runtime"
 	"time"
+	"workpool"
 )
 
 func main() {
@@ -83,46 +84,26 @@ func SerialSum(a []int) int {
 // Input: slice of integers a, numProcs integer
 // Output: sum of all elements in a, parallelized over numProcs workers
 func SumMultiProc(a []int, numProcs int) int {
-	n := len(a)
-	s := 0              // stores the sum
-	c := make(chan int) // used for exchange of information
-
 	//idea: split the job into numProcs pieces, each of approx equal size, and let numProcs workers compute the sum of one subslice of a
 
-	for i := 0; i < numProcs; i++ {
-		//figure out which start and end indices of a this worker is getting assigned
-		chunkSize := n / numProcs
-		startIndex := i * chunkSize
-		endIndex := startIndex + chunkSize
-
-		if i == numProcs-1 {
-			// if we're in the final subslice, we need to make sure to extend it all the way to the end of a
-			endIndex = n
-		}
-
-		go SumOneProc(a[startIndex:endIndex], c)
-	}
-
-	// once these are done, grab values from the channel
-	for i := 0; i < numProcs; i++ {
-		s += <-c
-		// this blocks too, which is what you want, otherwise you can't return s
-	}
-
-	return s
+	// workpool.Reduce figures out which start and end indices of a each worker is assigned, with the final subslice
+	// extending all the way to the end of a, gathers the sums of the workers through a channel, and adds them up
+	return workpool.Reduce(len(a), numProcs, func(worker, startIndex, endIndex int) int {
+		return SumOneProc(a[startIndex:endIndex])
+	}, func(s1, s2 int) int {
+		return s1 + s2
+	})
 }
 
 // SumOneProc
-// Input: slice of integers a, and an integer channel c
-// Output: nothing -- but puts sum of elements of a into c
-func SumOneProc(a []int, c chan int) {
+// Input: slice of integers a
+// Output: sum of elements of a, the share of the work done by one worker of SumMultiProc
+func SumOneProc(a []int) int {
 	s := 0
 	for _, val := range a {
 		s += val
 	}
-	c <- s // only blocks rest of this function
-
-	// but there is nothing down here
+	return s
 }
 
 func ParallelFactorial() {
@@ -130,26 +111,20 @@ func ParallelFactorial() {
 
 	n := 40
 
-	c := make(chan int)
-
-	go Perm(1, n/2+1, c)
-	go Perm(n/2+1, n, c)
-	fmt.Println(<-c * <-c) // concern is that print happens before functions even start, let alone finish. Go won't allow this
+	//Goroutines are barred from returning values, so printing Perm(1, n/2+1) * Perm(n/2+1, n+1) from two goroutines
+	//would print before the functions even start, let alone finish. Go won't allow this
 
-	//Goroutines are barred from returning values
-
-	// what we need is a method of communication across Goroutines for things like "here is a value" or "I am done"
-}
-
-// Perm computes n permuted with k from the prep materials
-func Perm(k, n int, c chan int) {
-	p := 1
-
-	for i := k; i < n; i++ {
-		p *= i
+	// what we need is a method of communication across Goroutines for things like "here is a value" or "I am done";
+	// FactorialMultiProc lets workpool run each half in its own goroutine and collect the values once both are done
+	p, err := FactorialMultiProc(n, 2)
+	if err == nil {
+		fmt.Println(p)
+		return
 	}
 
-	c <- p
+	// 40! has 48 digits, far more than the 19 an int can hold, so the product of the halves overflows
+	fmt.Println("Error:", err)
+	fmt.Println("With big integers instead:", BigFactorial(n, 2))
 }
 
 func SyncChannels() {
@@ -182,10 +157,11 @@ func IntroToConcurrency() {
 
 	fmt.Println("This computer has", runtime.NumCPU(), "total cores available.")
 
-	n := 100000000
+	// n! overflows an int past n = 20, so we time the big integer version, which splits the work over every core
+	n := 100000
 
 	start := time.Now()
-	Factorial(n)
+	BigFactorial(n, runtime.NumCPU())
 	elapsed := time.Since(start)
 	log.Printf("Using multiple processors took %s", elapsed)
 
@@ -198,7 +174,7 @@ func IntroToConcurrency() {
 	// let's do the same thing as before, but with Go only having access to one processor
 
 	start2 := time.Now()
-	Factorial(n)
+	BigFactorial(n, runtime.NumCPU())
 	elapsed2 := time.Since(start2)
 	log.Printf("Using one processor took %s", elapsed2)
 
@@ -223,11 +199,3 @@ func PrintFactorials(n int) {
 	}
 	fmt.Println("Exiting PrintFactorials function.")
 }
-
-func Factorial(n int) int {
-	prod := 1
-	for i := 2; i <= n; i++ {
-		prod *= i
-	}
-	return prod
-}
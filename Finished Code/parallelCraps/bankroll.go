@@ -0,0 +1,346 @@
+package main
+
+//this file contains betting strategies and simulates the sessions of a bettor following them at the craps table.
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"rng"
+	"workpool"
+)
+
+// Progression says how a strategy changes its stake after each bet is settled.
+type Progression int
+
+const (
+	Flat       Progression = iota // always bet the base stake
+	Martingale                    // double the stake after every loss and go back to the base stake after a win
+)
+
+// Strategy describes a bettor who keeps one bet of a single kind on the table, making a new one whenever the last is settled.
+type Strategy struct {
+	name         string
+	kind         BetKind
+	number       int // the number of a place bet
+	progression  Progression
+	baseBet      float64
+	oddsMultiple float64 // odds taken or laid behind a line or come bet once its point is set, as a multiple of the bet
+}
+
+// SessionSettings holds the rules of a session at the table.
+type SessionSettings struct {
+	bankroll float64 // the money the bettor starts with
+	maxRolls int     // the session ends after this many rolls
+	goal     float64 // the session ends once the bettor is worth this much; 0 for no goal
+	tableMax float64 // the largest bet the table accepts
+}
+
+// Session holds what happened to a bettor over one session.
+type Session struct {
+	trajectory  []float64 // what the bettor was worth, in hand and on the table, before the first roll and after every roll
+	ruined      bool      // true if the bettor ran out of money for the next bet
+	reachedGoal bool      // true if the bettor reached the goal
+	wagered     float64   // the total amount bet over the session, including odds
+}
+
+// Final is a Session method that returns what the bettor was worth at the end of the session.
+func (s Session) Final() float64 {
+	return s.trajectory[len(s.trajectory)-1]
+}
+
+// Rolls is a Session method that returns the number of rolls the session lasted.
+func (s Session) Rolls() int {
+	return len(s.trajectory) - 1
+}
+
+// DefaultStrategies returns the strategies compared by the bankroll study, all with a base bet of baseBet.
+func DefaultStrategies(baseBet float64) []Strategy {
+	return []Strategy{
+		{name: "flat pass", kind: PassLine, progression: Flat, baseBet: baseBet},
+		{name: "flat pass 2x odds", kind: PassLine, progression: Flat, baseBet: baseBet, oddsMultiple: 2},
+		{name: "martingale pass", kind: PassLine, progression: Martingale, baseBet: baseBet},
+		{name: "flat don't pass", kind: DontPassLine, progression: Flat, baseBet: baseBet},
+		{name: "flat come 1x odds", kind: Come, progression: Flat, baseBet: baseBet, oddsMultiple: 1},
+		{name: "flat field", kind: Field, progression: Flat, baseBet: baseBet},
+		{name: "martingale field", kind: Field, progression: Martingale, baseBet: baseBet},
+		{name: "flat place 6", kind: Place, number: 6, progression: Flat, baseBet: baseBet},
+	}
+}
+
+// NextStake is a Strategy method that takes the stake of a bet that was just settled and its outcome,
+// and returns the stake of the strategy's next bet.
+func (s Strategy) NextStake(stake float64, outcome Outcome) float64 {
+	if s.progression == Martingale {
+		if outcome == Lose {
+			return 2 * stake
+		} else if outcome == Win {
+			return s.baseBet
+		}
+		return stake
+	}
+	return s.baseBet
+}
+
+// Worth is a Table method that returns the money the player has on the table.
+func (t *Table) Worth() float64 {
+	worth := 0.0
+	for _, b := range t.bets {
+		worth += b.amount + b.odds
+	}
+	return worth
+}
+
+// PlaySession takes a Strategy, the SessionSettings, and a random stream, and plays one session at the table.
+// Before every roll, if the strategy has no bet on the table and the table allows one, it bets its stake, capped
+// by the table maximum and by the money in hand. The bettor is ruined once there is no bet on the table and less
+// than the base bet in hand. Bets still on the table when the session ends count at what was bet.
+func PlaySession(s Strategy, settings SessionSettings, r *rand.Rand) Session {
+	var session Session
+	var t Table
+
+	cash := settings.bankroll
+	stake := s.baseBet
+	var current *Bet // the strategy's bet on the table, or nil
+
+	session.trajectory = make([]float64, 1, settings.maxRolls+1)
+	session.trajectory[0] = cash
+
+	for roll := 0; roll < settings.maxRolls; roll++ {
+		if current == nil && t.CanMake(s.kind, s.number) == nil {
+			amount := math.Min(math.Min(stake, settings.tableMax), cash)
+			if amount >= s.baseBet {
+				cash -= amount
+				session.wagered += amount
+				current = t.MakeBet(s.kind, amount, s.number)
+			}
+		}
+
+		if len(t.bets) == 0 && cash < s.baseBet {
+			session.ruined = true
+			break
+		}
+
+		for _, settlement := range t.Roll(SumTwoDice(r)) {
+			cash += settlement.returned
+			if settlement.bet == current {
+				stake = s.NextStake(stake, settlement.outcome)
+				current = nil
+			}
+		}
+
+		// back a line or come bet with odds once its point is set
+		if current != nil && s.oddsMultiple > 0 && current.kind != Place && current.number != 0 && current.odds == 0 {
+			odds := math.Min(s.oddsMultiple*current.amount, cash)
+			if odds > 0 {
+				cash -= odds
+				session.wagered += odds
+				t.TakeOdds(current, odds)
+			}
+		}
+
+		worth := cash + t.Worth()
+		session.trajectory = append(session.trajectory, worth)
+
+		if settings.goal > 0 && worth >= settings.goal {
+			session.reachedGoal = true
+			break
+		}
+	}
+
+	return session
+}
+
+// SimulateSessions takes a Strategy, the SessionSettings, a number of sessions, a number of processors, and a seed.
+// It plays numSessions independent sessions split over numProcs workers with workpool.For,
+// with worker i drawing from the stream rng.DeriveSeed(seed, i), and returns every session in order.
+func SimulateSessions(s Strategy, settings SessionSettings, numSessions, numProcs int, seed int64) []Session {
+	sessions := make([]Session, numSessions)
+	streams := rng.Streams(seed, numProcs)
+
+	workpool.For(numSessions, numProcs, func(worker, start, end int) {
+		PlaySessionsOneProc(s, settings, sessions[start:end], streams[worker])
+	})
+
+	return sessions
+}
+
+// PlaySessionsOneProc takes a Strategy, the SessionSettings, a slice of Sessions, and a random stream,
+// and plays a session into every element of the slice.
+func PlaySessionsOneProc(s Strategy, settings SessionSettings, sessions []Session, r *rand.Rand) {
+	for i := range sessions {
+		sessions[i] = PlaySession(s, settings, r)
+	}
+}
+
+// RuinCurve takes a slice of Sessions and a number of rolls, and returns for every roll from 0 to maxRolls
+// the fraction of sessions ruined by then, along with the mean worth of the bettors then. A session that has
+// ended counts at its final worth.
+func RuinCurve(sessions []Session, maxRolls int) ([]float64, []float64) {
+	ruined := make([]float64, maxRolls+1)
+	meanWorth := make([]float64, maxRolls+1)
+
+	for _, session := range sessions {
+		for t := 0; t <= maxRolls; t++ {
+			if t < len(session.trajectory) {
+				meanWorth[t] += session.trajectory[t]
+			} else {
+				meanWorth[t] += session.Final()
+			}
+			// the last worth of the trajectory is the one after the roll that ruined the bettor
+			if session.ruined && t >= len(session.trajectory)-1 {
+				ruined[t]++
+			}
+		}
+	}
+
+	for t := range ruined {
+		ruined[t] /= float64(len(sessions))
+		meanWorth[t] /= float64(len(sessions))
+	}
+
+	return ruined, meanWorth
+}
+
+// SessionSummary holds the statistics of many sessions played with one strategy.
+type SessionSummary struct {
+	meanFinal, stdErr float64 // the mean final worth and its standard error
+	ruin, goal        float64 // the fractions of sessions ending in ruin and at the goal
+	meanRolls         float64
+	edge              float64 // the money lost per unit wagered over all sessions
+}
+
+// SummarizeSessions takes a slice of Sessions and the starting bankroll, and returns their SessionSummary.
+func SummarizeSessions(sessions []Session, bankroll float64) SessionSummary {
+	var summary SessionSummary
+	n := float64(len(sessions))
+
+	finals := make([]float64, len(sessions))
+	wagered := 0.0
+	for i, session := range sessions {
+		finals[i] = session.Final()
+		summary.meanFinal += finals[i] / n
+		summary.meanRolls += float64(session.Rolls()) / n
+		wagered += session.wagered
+		if session.ruined {
+			summary.ruin += 1 / n
+		}
+		if session.reachedGoal {
+			summary.goal += 1 / n
+		}
+	}
+
+	variance := 0.0
+	for _, x := range finals {
+		variance += (x - summary.meanFinal) * (x - summary.meanFinal)
+	}
+	if len(finals) > 1 {
+		summary.stdErr = math.Sqrt(variance/(n-1)) / math.Sqrt(n)
+	}
+
+	if wagered > 0 {
+		summary.edge = (bankroll - summary.meanFinal) * n / wagered
+	}
+
+	return summary
+}
+
+// OutcomeHistogram takes a slice of Sessions, a number of bins, and the largest worth to bin, and returns the number
+// of sessions whose final worth falls in each of numBins equal bins from 0 to max. Worth of max or more goes in the last bin.
+func OutcomeHistogram(sessions []Session, numBins int, max float64) []int {
+	counts := make([]int, numBins)
+	binWidth := max / float64(numBins)
+
+	for _, session := range sessions {
+		k := int(session.Final() / binWidth)
+		if k >= numBins {
+			k = numBins - 1
+		}
+		if k < 0 {
+			k = 0
+		}
+		counts[k]++
+	}
+
+	return counts
+}
+
+// WriteRuinToFile takes the names of strategies, the ruin curve and mean worth of each, and a file name,
+// and writes one line per strategy and roll to a CSV file.
+func WriteRuinToFile(names []string, ruined, meanWorth [][]float64, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, "strategy,roll,ruined,meanWorth")
+	for i, name := range names {
+		for t := range ruined[i] {
+			fmt.Fprintf(writer, "%s,%d,%g,%g\n", name, t, ruined[i][t], meanWorth[i][t])
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// WriteOutcomesToFile takes the names of strategies, the histogram of final worth of each, the width of a bin,
+// the number of sessions, and a file name, and writes one line per strategy and bin to a CSV file.
+func WriteOutcomesToFile(names []string, histograms [][]int, binWidth float64, numSessions int, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, "strategy,low,high,count,fraction")
+	for i, name := range names {
+		for k, count := range histograms[i] {
+			fmt.Fprintf(writer, "%s,%g,%g,%d,%g\n", name, float64(k)*binWidth, float64(k+1)*binWidth, count, float64(count)/float64(numSessions))
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// BankrollStudy takes a slice of Strategies, the SessionSettings, a number of sessions, a number of processors, and
+// a seed. It simulates numSessions sessions of every strategy, prints a summary of each, and writes the risk of ruin
+// over time to ruin.csv and the distribution of final worth to sessions.csv.
+func BankrollStudy(strategies []Strategy, settings SessionSettings, numSessions, numProcs int, seed int64) {
+	names := make([]string, len(strategies))
+	ruined := make([][]float64, len(strategies))
+	meanWorth := make([][]float64, len(strategies))
+	histograms := make([][]int, len(strategies))
+
+	// bin final worth up to twice the starting bankroll, or up to the goal if that is larger
+	numBins := 40
+	max := math.Max(2*settings.bankroll, settings.goal)
+
+	for i, s := range strategies {
+		// each strategy gets its own seed, so adding a strategy doesn't change the sessions of the others
+		sessions := SimulateSessions(s, settings, numSessions, numProcs, rng.DeriveSeedFromKey(seed, s.name))
+
+		names[i] = s.name
+		ruined[i], meanWorth[i] = RuinCurve(sessions, settings.maxRolls)
+		histograms[i] = OutcomeHistogram(sessions, numBins, max)
+
+		summary := SummarizeSessions(sessions, settings.bankroll)
+		fmt.Printf("%-18s final %.2f +/- %.2f, ruined %.2f%%, reached goal %.2f%%, %.1f rolls on average, edge %.3f%% of money wagered\n",
+			s.name, summary.meanFinal, summary.stdErr, 100*summary.ruin, 100*summary.goal, summary.meanRolls, 100*summary.edge)
+	}
+
+	WriteRuinToFile(names, ruined, meanWorth, "ruin.csv")
+	WriteOutcomesToFile(names, histograms, max/float64(numBins), numSessions, "sessions.csv")
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestRuinCurve tests that a session counts as ruined from the roll that ruined it, and at its final worth after it ends.
+func TestRuinCurve(t *testing.T) {
+	sessions := []Session{
+		{trajectory: []float64{10, 5, 0}, ruined: true}, // ruined by the second roll
+		{trajectory: []float64{10, 15, 20, 25}},         // stopped at the goal after three rolls
+	}
+
+	ruined, meanWorth := RuinCurve(sessions, 4)
+
+	wantRuined := []float64{0, 0, 0.5, 0.5, 0.5}
+	wantWorth := []float64{10, 10, 10, 12.5, 12.5}
+	for i := range wantRuined {
+		if ruined[i] != wantRuined[i] || meanWorth[i] != wantWorth[i] {
+			t.Errorf("roll %d: ruined %g with mean worth %g, want %g and %g", i, ruined[i], meanWorth[i], wantRuined[i], wantWorth[i])
+		}
+	}
+}
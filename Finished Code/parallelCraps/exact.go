@@ -0,0 +1,301 @@
+package main
+
+//this file computes the exact odds of craps bets by treating a game as an absorbing Markov chain.
+//all arithmetic is done with rational numbers, so the answers are exact fractions rather than estimates.
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Points holds the sums that become the point when rolled on the come-out roll.
+var Points = []int{4, 5, 6, 8, 9, 10}
+
+// DiceSumWays takes a sum and returns the number of the 36 rolls of two dice that give it.
+func DiceSumWays(sum int) int {
+	if sum < 2 || sum > 12 {
+		return 0
+	}
+	if sum <= 7 {
+		return sum - 1
+	}
+	return 13 - sum
+}
+
+// DiceSumProbability takes a sum and returns the exact probability that two dice give it.
+func DiceSumProbability(sum int) *big.Rat {
+	return big.NewRat(int64(DiceSumWays(sum)), 36)
+}
+
+// MarkovChain is an absorbing Markov chain whose first numTransient states are transient and whose other
+// states are absorbing. transitions[i][j] is the probability of moving from state i to state j in one step.
+type MarkovChain struct {
+	states       []string
+	numTransient int
+	transitions  [][]*big.Rat
+}
+
+// MakeMarkovChain takes the names of the transient states and the names of the absorbing states, and returns a
+// MarkovChain over them with every transition probability 0.
+func MakeMarkovChain(transient, absorbing []string) MarkovChain {
+	var m MarkovChain
+	m.states = append(append([]string{}, transient...), absorbing...)
+	m.numTransient = len(transient)
+
+	m.transitions = make([][]*big.Rat, len(m.states))
+	for i := range m.transitions {
+		m.transitions[i] = make([]*big.Rat, len(m.states))
+		for j := range m.transitions[i] {
+			m.transitions[i][j] = new(big.Rat)
+		}
+	}
+
+	return m
+}
+
+// StateIndex is a MarkovChain method that takes the name of a state and returns its index. It panics if there is no such state.
+func (m MarkovChain) StateIndex(name string) int {
+	for i := range m.states {
+		if m.states[i] == name {
+			return i
+		}
+	}
+	panic("Error: no state named " + name + " in Markov chain.")
+}
+
+// AddTransition is a MarkovChain method that takes two states and a probability, and adds the probability to the
+// transition from the first state to the second.
+func (m MarkovChain) AddTransition(from, to string, p *big.Rat) {
+	t := m.transitions[m.StateIndex(from)][m.StateIndex(to)]
+	t.Add(t, p)
+}
+
+// Validate is a MarkovChain method that returns an error if the transitions out of some transient state don't sum to 1.
+func (m MarkovChain) Validate() error {
+	one := big.NewRat(1, 1)
+	for i := 0; i < m.numTransient; i++ {
+		total := new(big.Rat)
+		for j := range m.states {
+			total.Add(total, m.transitions[i][j])
+		}
+		if total.Cmp(one) != 0 {
+			return fmt.Errorf("transitions out of state %s sum to %s, not 1", m.states[i], total.RatString())
+		}
+	}
+	return nil
+}
+
+// AbsorptionProbabilities is a MarkovChain method that returns the matrix B in which B[i][k] is the probability
+// that the chain, started in transient state i, is eventually absorbed in absorbing state k.
+// Writing Q for the transitions among transient states and R for those from transient to absorbing states,
+// B solves (I - Q) B = R, which is found exactly by Gauss-Jordan elimination.
+func (m MarkovChain) AbsorptionProbabilities() [][]*big.Rat {
+	n := m.numTransient
+	numAbsorbing := len(m.states) - n
+
+	// build the augmented matrix [I - Q | R]
+	rows := make([][]*big.Rat, n)
+	for i := range rows {
+		rows[i] = make([]*big.Rat, n+numAbsorbing)
+		for j := 0; j < n; j++ {
+			rows[i][j] = new(big.Rat).Neg(m.transitions[i][j])
+			if i == j {
+				rows[i][j].Add(rows[i][j], big.NewRat(1, 1))
+			}
+		}
+		for k := 0; k < numAbsorbing; k++ {
+			rows[i][n+k] = new(big.Rat).Set(m.transitions[i][n+k])
+		}
+	}
+
+	for col := 0; col < n; col++ {
+		// find a row with a nonzero pivot; one always exists when every transient state can reach an absorbing state
+		pivot := -1
+		for i := col; i < n; i++ {
+			if rows[i][col].Sign() != 0 {
+				pivot = i
+				break
+			}
+		}
+		if pivot == -1 {
+			panic("Error: Markov chain has transient states that are never absorbed.")
+		}
+		rows[col], rows[pivot] = rows[pivot], rows[col]
+
+		// scale the pivot row so the pivot is 1
+		scale := new(big.Rat).Inv(rows[col][col])
+		for j := range rows[col] {
+			rows[col][j].Mul(rows[col][j], scale)
+		}
+
+		// clear the column in every other row
+		for i := 0; i < n; i++ {
+			if i == col || rows[i][col].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Rat).Set(rows[i][col])
+			for j := range rows[i] {
+				rows[i][j].Sub(rows[i][j], new(big.Rat).Mul(factor, rows[col][j]))
+			}
+		}
+	}
+
+	b := make([][]*big.Rat, n)
+	for i := range b {
+		b[i] = rows[i][n:]
+	}
+	return b
+}
+
+// Absorption is a MarkovChain method that takes a transient state and an absorbing state, and returns the
+// probability that the chain started in the first ends in the second.
+func (m MarkovChain) Absorption(from, to string) *big.Rat {
+	return m.AbsorptionProbabilities()[m.StateIndex(from)][m.StateIndex(to)-m.numTransient]
+}
+
+// PointState takes a point and returns the name of the state in which that point has been set.
+func PointState(point int) string {
+	return fmt.Sprintf("point %d", point)
+}
+
+// PassLineChain returns the Markov chain of a pass line bet, from the come-out roll until the bet wins or loses.
+// On the come-out roll, 7 or 11 wins, 2, 3 or 12 loses, and any other sum becomes the point. Once a point
+// is set, rolling it again wins and rolling 7 loses, while every other roll leaves the chain where it is.
+// A come bet follows the same chain, starting with the roll after it is made.
+func PassLineChain() MarkovChain {
+	return crapsChain(
+		map[int]string{7: "win", 11: "win", 2: "lose", 3: "lose", 12: "lose"},
+		"win", "lose", []string{"win", "lose"})
+}
+
+// DontPassChain returns the Markov chain of a don't pass bet, which mostly bets against the pass line.
+// On the come-out roll, 2 or 3 wins, 7 or 11 loses, and 12 is a push that returns the bet. Once a point
+// is set, rolling 7 wins and rolling the point loses.
+func DontPassChain() MarkovChain {
+	return crapsChain(
+		map[int]string{2: "win", 3: "win", 7: "lose", 11: "lose", 12: "push"},
+		"lose", "win", []string{"win", "lose", "push"})
+}
+
+// crapsChain takes what each come-out sum that isn't a point leads to, what making the point leads to,
+// what sevening out leads to, and the absorbing states, and returns the chain of a bet that is settled by a point.
+func crapsChain(comeOut map[int]string, pointMade, sevenOut string, absorbing []string) MarkovChain {
+	transient := []string{"come-out"}
+	for _, point := range Points {
+		transient = append(transient, PointState(point))
+	}
+
+	m := MakeMarkovChain(transient, absorbing)
+
+	for sum := 2; sum <= 12; sum++ {
+		if outcome, ok := comeOut[sum]; ok {
+			m.AddTransition("come-out", outcome, DiceSumProbability(sum))
+		} else {
+			m.AddTransition("come-out", PointState(sum), DiceSumProbability(sum))
+		}
+	}
+
+	for _, point := range Points {
+		state := PointState(point)
+		for sum := 2; sum <= 12; sum++ {
+			switch sum {
+			case point:
+				m.AddTransition(state, pointMade, DiceSumProbability(sum))
+			case 7:
+				m.AddTransition(state, sevenOut, DiceSumProbability(sum))
+			default:
+				m.AddTransition(state, state, DiceSumProbability(sum))
+			}
+		}
+	}
+
+	return m
+}
+
+// ExactBet holds the exact probabilities that a bet wins, loses, or pushes, what a win pays for every 1 bet,
+// and the resulting expected payoff of a bet of 1.
+type ExactBet struct {
+	name           string
+	win, lose      *big.Rat
+	push           *big.Rat
+	payout         *big.Rat
+	expectedPayoff *big.Rat
+}
+
+// MakeExactBet takes a name and the exact probabilities of winning, losing and pushing, along with the payout
+// of a win, and returns the ExactBet with its expected payoff, payout * win - lose.
+func MakeExactBet(name string, win, lose, push, payout *big.Rat) ExactBet {
+	expected := new(big.Rat).Mul(payout, win)
+	expected.Sub(expected, lose)
+	return ExactBet{name: name, win: win, lose: lose, push: push, payout: payout, expectedPayoff: expected}
+}
+
+// ExactPassLine returns the exact odds of a pass line bet, which pays even money.
+func ExactPassLine() ExactBet {
+	m := PassLineChain()
+	return MakeExactBet("pass", m.Absorption("come-out", "win"), m.Absorption("come-out", "lose"), new(big.Rat), big.NewRat(1, 1))
+}
+
+// ExactDontPass returns the exact odds of a don't pass bet, which pays even money.
+func ExactDontPass() ExactBet {
+	m := DontPassChain()
+	return MakeExactBet("don't pass", m.Absorption("come-out", "win"), m.Absorption("come-out", "lose"), m.Absorption("come-out", "push"), big.NewRat(1, 1))
+}
+
+// ExactCome returns the exact odds of a come bet. It is settled by the rolls after it is made exactly as a pass
+// line bet is settled from the come-out roll, so its odds are the same.
+func ExactCome() ExactBet {
+	bet := ExactPassLine()
+	bet.name = "come"
+	return bet
+}
+
+// OddsPayout takes a point and returns what a free odds bet behind the pass line pays for every 1 bet once
+// that point is set: the true odds against making the point, 2 to 1 on 4 and 10, 3 to 2 on 5 and 9,
+// and 6 to 5 on 6 and 8.
+func OddsPayout(point int) *big.Rat {
+	return big.NewRat(6, int64(DiceSumWays(point)))
+}
+
+// ExactOdds takes a point and returns the exact odds of a free odds bet on it, starting from the roll after the
+// point is set. Because the bet pays true odds, its expected payoff is 0.
+func ExactOdds(point int) ExactBet {
+	m := PassLineChain()
+	state := PointState(point)
+	return MakeExactBet(fmt.Sprintf("odds on %d", point), m.Absorption(state, "win"), m.Absorption(state, "lose"), new(big.Rat), OddsPayout(point))
+}
+
+// PointProbability returns the exact probability that the come-out roll sets a point.
+func PointProbability() *big.Rat {
+	p := new(big.Rat)
+	for _, point := range Points {
+		p.Add(p, DiceSumProbability(point))
+	}
+	return p
+}
+
+// ExactPassWithOdds takes a multiple and returns the exact expected payoff of a pass line bet of 1 backed by
+// an odds bet of multiple once a point is set, together with the house edge per unit actually wagered.
+// The odds bet adds nothing to the expected payoff, but it is only made two times in three, so it lowers the
+// edge on the money put at risk.
+func ExactPassWithOdds(multiple int) (*big.Rat, *big.Rat) {
+	expected := new(big.Rat).Set(ExactPassLine().expectedPayoff)
+
+	// the odds are only wagered when a point is set
+	wagered := new(big.Rat).Mul(big.NewRat(int64(multiple), 1), PointProbability())
+	wagered.Add(wagered, big.NewRat(1, 1))
+
+	edge := new(big.Rat).Neg(expected)
+	edge.Quo(edge, wagered)
+
+	return expected, edge
+}
+
+// ExactBets returns the exact odds of the pass, don't pass and come bets, and of the odds bet on every point.
+func ExactBets() []ExactBet {
+	bets := []ExactBet{ExactPassLine(), ExactDontPass(), ExactCome()}
+	for _, point := range Points {
+		bets = append(bets, ExactOdds(point))
+	}
+	return bets
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestExactPassLine tests that the pass line wins 244/495 of the time and loses 7/495 for every 1 bet.
+func TestExactPassLine(t *testing.T) {
+	bet := ExactPassLine()
+	if bet.win.Cmp(big.NewRat(244, 495)) != 0 {
+		t.Errorf("pass line wins %s, want 244/495", bet.win.RatString())
+	}
+	if bet.lose.Cmp(big.NewRat(251, 495)) != 0 {
+		t.Errorf("pass line loses %s, want 251/495", bet.lose.RatString())
+	}
+	if bet.expectedPayoff.Cmp(big.NewRat(-7, 495)) != 0 {
+		t.Errorf("pass line expected payoff = %s, want -7/495", bet.expectedPayoff.RatString())
+	}
+}
+
+// TestExactDontPass tests that don't pass pushes on 1/36 of bets and loses 3/220 for every 1 bet.
+func TestExactDontPass(t *testing.T) {
+	bet := ExactDontPass()
+	if bet.push.Cmp(big.NewRat(1, 36)) != 0 {
+		t.Errorf("don't pass pushes %s, want 1/36", bet.push.RatString())
+	}
+	if bet.expectedPayoff.Cmp(big.NewRat(-3, 220)) != 0 {
+		t.Errorf("don't pass expected payoff = %s, want -3/220", bet.expectedPayoff.RatString())
+	}
+
+	// every bet ends in exactly one of the three outcomes
+	total := new(big.Rat).Add(bet.win, bet.lose)
+	total.Add(total, bet.push)
+	if total.Cmp(big.NewRat(1, 1)) != 0 {
+		t.Errorf("don't pass outcomes sum to %s, want 1", total.RatString())
+	}
+}
+
+// TestExactOdds tests that the odds bet on every point pays true odds, so its expected payoff is exactly 0.
+func TestExactOdds(t *testing.T) {
+	for _, point := range Points {
+		bet := ExactOdds(point)
+		if bet.expectedPayoff.Sign() != 0 {
+			t.Errorf("odds on %d: expected payoff = %s, want 0", point, bet.expectedPayoff.RatString())
+		}
+	}
+}
+
+// TestValidateExactBets tests that every simulated bet agrees with its exact odds for a fixed seed, and that
+// each Validation is for the bet its simulation was paired with.
+func TestValidateExactBets(t *testing.T) {
+	bets := ExactBets()
+	validations := ValidateExactBets(200000, 4, 1, 0.999)
+	if len(validations) != len(bets) {
+		t.Fatalf("got %d validations for %d bets", len(validations), len(bets))
+	}
+
+	for i, v := range validations {
+		if v.bet.name != bets[i].name {
+			t.Errorf("validation %d is for %s, want %s", i, v.bet.name, bets[i].name)
+		}
+		if !v.ok {
+			t.Errorf("%s: simulated %f +/- %f, exact %f (z = %.2f)",
+				v.bet.name, v.simulated.Mean(), v.simulated.StdErr(), v.exact, v.z)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+//this file contains casino games written as Monte Carlo trials, each returning the money won on a bet of 1.
+
+import (
+	"fmt"
+	"math/rand"
+	"montecarlo"
+	"sort"
+)
+
+// Games maps the name of each game to its trial.
+var Games = map[string]montecarlo.Trial{
+	"craps":      CrapsPassLine,
+	"dontpass":   DontPass,
+	"come":       ComeBet,
+	"red":        RouletteRed,
+	"straightup": RouletteStraightUp,
+	"chuckaluck": ChuckALuck,
+}
+
+// GameNames returns the names of the games in Games in alphabetical order.
+func GameNames() []string {
+	names := make([]string, 0, len(Games))
+	for name := range Games {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GameByName takes the name of a game and returns its trial.
+func GameByName(name string) (montecarlo.Trial, error) {
+	trial, ok := Games[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown game %s; choose from %v", name, GameNames())
+	}
+	return trial, nil
+}
+
+// CrapsPassLine takes a random stream and returns the payoff of a pass line bet of 1 on one game of craps:
+// 1 if the shooter wins and -1 if not.
+func CrapsPassLine(r *rand.Rand) float64 {
+	if PlayCrapsOnce(r) {
+		return 1
+	}
+	return -1
+}
+
+// IsPoint takes a sum of two dice and returns true if it becomes the point when rolled on the come-out roll.
+func IsPoint(sum int) bool {
+	return sum == 4 || sum == 5 || sum == 6 || sum == 8 || sum == 9 || sum == 10
+}
+
+// MakePoint takes a point and a random stream, and rolls until either the point or a 7 comes up.
+// It returns true if the point is made.
+func MakePoint(point int, r *rand.Rand) bool {
+	for {
+		roll := SumTwoDice(r)
+		if roll == point {
+			return true
+		} else if roll == 7 {
+			return false
+		}
+	}
+}
+
+// DontPass takes a random stream and returns the payoff of a don't pass bet of 1 on one game of craps.
+// On the come-out roll, 2 or 3 wins, 7 or 11 loses, and 12 pushes, paying 0. Once a point is set, a 7 wins
+// and the point loses.
+func DontPass(r *rand.Rand) float64 {
+	firstRoll := SumTwoDice(r)
+	switch firstRoll {
+	case 2, 3:
+		return 1
+	case 7, 11:
+		return -1
+	case 12:
+		return 0
+	}
+
+	if MakePoint(firstRoll, r) {
+		return -1
+	}
+	return 1
+}
+
+// ComeBet takes a random stream and returns the payoff of a come bet of 1. Come bets can only be made once the
+// pass line has a point, so it first rolls until a point is set, then plays the bet from the next roll on.
+func ComeBet(r *rand.Rand) float64 {
+	for !IsPoint(SumTwoDice(r)) {
+		// the pass line bet was settled on the come-out roll, so a new game starts
+	}
+	return CrapsPassLine(r)
+}
+
+// OddsBet takes a point and returns a trial giving the payoff of a free odds bet of 1 on that point, made once the
+// point is set. It pays the true odds OddsPayout(point) if the point is made and loses 1 on a 7.
+func OddsBet(point int) montecarlo.Trial {
+	payout, _ := OddsPayout(point).Float64()
+	return func(r *rand.Rand) float64 {
+		if MakePoint(point, r) {
+			return payout
+		}
+		return -1
+	}
+}
+
+// PassWithOdds takes a multiple and returns a trial giving the payoff of a pass line bet of 1 that is backed
+// by an odds bet of multiple whenever a point is set.
+func PassWithOdds(multiple int) montecarlo.Trial {
+	return func(r *rand.Rand) float64 {
+		firstRoll := SumTwoDice(r)
+		if firstRoll == 7 || firstRoll == 11 {
+			return 1
+		} else if firstRoll == 2 || firstRoll == 3 || firstRoll == 12 {
+			return -1
+		}
+
+		payout, _ := OddsPayout(firstRoll).Float64()
+		if MakePoint(firstRoll, r) {
+			return 1 + float64(multiple)*payout
+		}
+		return -1 - float64(multiple)
+	}
+}
+
+// SpinRoulette takes a random stream and returns the pocket of an American roulette wheel the ball lands in,
+// from 0 to 36, with 37 standing for 00.
+func SpinRoulette(r *rand.Rand) int {
+	return r.Intn(38)
+}
+
+// redPockets holds the numbers that are red on a roulette wheel.
+var redPockets = map[int]bool{
+	1: true, 3: true, 5: true, 7: true, 9: true, 12: true, 14: true, 16: true, 18: true,
+	19: true, 21: true, 23: true, 25: true, 27: true, 30: true, 32: true, 34: true, 36: true,
+}
+
+// RouletteRed takes a random stream and returns the payoff of a bet of 1 on red, which pays even money.
+// 18 of the 38 pockets are red, so the house edge is 2/38.
+func RouletteRed(r *rand.Rand) float64 {
+	if redPockets[SpinRoulette(r)] {
+		return 1
+	}
+	return -1
+}
+
+// RouletteStraightUp takes a random stream and returns the payoff of a bet of 1 on the single number 17,
+// which pays 35 to 1. The house edge is also 2/38, but the variance is far larger than a bet on red.
+func RouletteStraightUp(r *rand.Rand) float64 {
+	if SpinRoulette(r) == 17 {
+		return 35
+	}
+	return -1
+}
+
+// ChuckALuck takes a random stream and returns the payoff of a bet of 1 on the number 6 in chuck-a-luck.
+// Three dice are rolled, and the bet wins 1 for each die showing 6, or loses 1 if none do.
+// The house edge is 17/216.
+func ChuckALuck(r *rand.Rand) float64 {
+	matches := 0
+	for i := 0; i < 3; i++ {
+		if RollDie(r) == 6 {
+			matches++
+		}
+	}
+
+	if matches == 0 {
+		return -1
+	}
+	return float64(matches)
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"montecarlo"
+	"os"
+	"rng"
 	"runtime"
 	"time"
 )
@@ -11,15 +14,78 @@ func main() {
 	numTrials := 10000000
 	numProcs := runtime.NumCPU()
 
+	// "bankroll" as the first argument runs the bankroll study instead of estimating house edges
+	args := os.Args[1:]
+	bankrollStudy := len(args) > 0 && args[0] == "bankroll"
+	if bankrollStudy {
+		args = args[1:]
+	}
+
+	// an optional seed on the command line repeats an earlier run; otherwise the seed comes from the clock
+	seedString := "time"
+	if len(args) > 0 {
+		seedString = args[0]
+	}
+	seed, err := rng.ParseSeed(seedString)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Seed:", seed)
+
+	if bankrollStudy {
+		// start with 200, bet 10 at a time, and stop after 1000 rolls or on doubling the bankroll
+		var settings SessionSettings
+		settings.bankroll = 200.0
+		settings.maxRolls = 1000
+		settings.goal = 400.0
+		settings.tableMax = 500.0
+
+		numSessions := 10000
+
+		fmt.Println("Simulating", numSessions, "sessions of each strategy.")
+		BankrollStudy(DefaultStrategies(10.0), settings, numSessions, numProcs, seed)
+		fmt.Println("Risk of ruin written to ruin.csv, distribution of session outcomes to sessions.csv.")
+		return
+	}
+
 	start := time.Now()
-	ComputeHouseEdge(numTrials)
+	ComputeHouseEdge(numTrials, seed)
 	elapsed := time.Since(start)
 	fmt.Printf("Running serially took %s", elapsed)
 	fmt.Println()
 
 	start2 := time.Now()
-	ComputeHouseEdgeMultiproc(numTrials, numProcs)
+	ComputeHouseEdgeMultiproc(numTrials, numProcs, seed)
 	elapsed2 := time.Since(start2)
 	fmt.Printf("Running in parallel took %s", elapsed2)
+	fmt.Println()
+
+	// now estimate the edge of every game until the standard error is at most 0.001, or give up after 100 million bets
+	target := 0.001
+	batchSize := 100000
+	maxTrials := 100000000
+
+	for _, name := range GameNames() {
+		summary, ok := montecarlo.RunToPrecision(Games[name], target, batchSize, maxTrials, numProcs, seed)
+		low, high := summary.ConfidenceInterval(0.95)
+
+		fmt.Printf("%s: mean payoff %.5f, std. error %.5f, 95%% interval [%.5f, %.5f] after %d bets",
+			name, summary.Mean(), summary.StdErr(), low, high, summary.N())
+		if !ok {
+			fmt.Print(" (target precision not reached)")
+		}
+		fmt.Println()
+	}
+
+	// finally, check the simulated craps bets against the exact odds of the Markov chain
+	fmt.Println("Exact odds of craps bets, against 1 million simulated bets each:")
+	validations := ValidateExactBets(1000000, numProcs, seed, 0.99)
+	PrintExactBets(validations)
 
+	for _, multiple := range []int{1, 2, 5} {
+		expected, edge := ExactPassWithOdds(multiple)
+		edgeFloat, _ := edge.Float64()
+		fmt.Printf("Pass line with %dx odds: exact payoff %s per pass bet, house edge %s = %.4f%% of the money wagered\n",
+			multiple, expected.RatString(), edge.RatString(), 100*edgeFloat)
+	}
 }
@@ -2,32 +2,36 @@ package main
 
 import (
 	"math/rand"
+	"montecarlo"
 )
 
-func RollDie() int {
-	return rand.Intn(6) + 1
+// RollDie takes a random stream and returns the roll of a simulated six-sided die.
+func RollDie(r *rand.Rand) int {
+	return r.Intn(6) + 1
 }
 
-// SumTwoDice takes no inputs and returns the sum of two simulated six-sided dice.
-func SumTwoDice() int {
-	return RollDie() + RollDie()
+// SumTwoDice takes a random stream and returns the sum of two simulated six-sided dice.
+func SumTwoDice(r *rand.Rand) int {
+	return RollDie(r) + RollDie(r)
 }
 
 // math/rand has three built-in functions we will use a lot:
 // 1. rand.Int: pseudorandom integer
 // 2. rand.Float64: pseudorandom decimal in [0, 1)
 // 3. rand.Intn: pseudorandom integer between 0 and n-1, inclusively
+// A *rand.Rand has the same three as methods. Unlike the global functions, which share one source between
+// every goroutine, it gives the same numbers every time it is made with the same seed.
 
-// PlayCrapsOnce takes no input parameters and returns true or false depending on outcome of a single simulated game of craps.
-func PlayCrapsOnce() bool {
-	firstRoll := SumTwoDice()
+// PlayCrapsOnce takes a random stream and returns true or false depending on outcome of a single simulated game of craps.
+func PlayCrapsOnce(r *rand.Rand) bool {
+	firstRoll := SumTwoDice(r)
 	if firstRoll == 7 || firstRoll == 11 {
 		return true // winner!
 	} else if firstRoll == 2 || firstRoll == 3 || firstRoll == 12 {
 		return false // loser!
 	} else { //roll again until we hit a 7 or our original roll
 		for true {
-			newRoll := SumTwoDice()
+			newRoll := SumTwoDice(r)
 			if newRoll == firstRoll {
 				// winner! :)
 				return true
@@ -42,21 +46,9 @@ func PlayCrapsOnce() bool {
 	return false
 }
 
-// ComputeHouseEdge takes an integer numTrials and returns an estimate of the house edge of craps (or whatever binary game) played over numTrials simulated games.
-func ComputeHouseEdge(numTrials int) float64 {
-	// we use count to keep track of money won/lost
-	count := 0
-	for i := 0; i < numTrials; i++ {
-		var outcome bool
-		outcome = PlayCrapsOnce()
-		// did we win or lose?
-		if outcome == true { // win
-			count++
-		} else {
-			// lost
-			count--
-		}
-	}
+// ComputeHouseEdge takes an integer numTrials and a seed, and returns an estimate of the house edge of craps (or whatever binary game) played over numTrials simulated games.
+// It plays every game on one worker, drawing from the first of the streams derived from seed, so it plays the same games as ComputeHouseEdgeMultiproc on one processor.
+func ComputeHouseEdge(numTrials int, seed int64) float64 {
 	// we want to return the average won/lost
-	return float64(count) / float64(numTrials)
+	return montecarlo.Run(CrapsPassLine, numTrials, 1, seed).Mean()
 }
@@ -0,0 +1,252 @@
+package main
+
+//this file contains a craps table on which several kinds of bets can be made and settled roll by roll.
+
+import (
+	"fmt"
+)
+
+// BetKind says what a bet is on.
+type BetKind int
+
+const (
+	PassLine     BetKind = iota // made on the come-out roll; wins on 7 or 11, loses on 2, 3 or 12, and otherwise on the point
+	DontPassLine                // made on the come-out roll; the reverse of the pass line, except that 12 pushes
+	Come                        // made once there is a point; settled like a pass line bet from the next roll on
+	Field                       // one roll: wins on 2, 3, 4, 9, 10, 11 or 12, paying double on 2 and triple on 12
+	Place                       // on one of the point numbers; wins if it comes before a 7, and is off on come-out rolls
+)
+
+// Outcome says how a bet was settled.
+type Outcome int
+
+const (
+	Win Outcome = iota
+	Lose
+	Push
+)
+
+// BetKindByName takes the name of a kind of bet ("pass", "dontpass", "come", "field", or "place") and returns it.
+func BetKindByName(name string) (BetKind, error) {
+	switch name {
+	case "pass":
+		return PassLine, nil
+	case "dontpass":
+		return DontPassLine, nil
+	case "come":
+		return Come, nil
+	case "field":
+		return Field, nil
+	case "place":
+		return Place, nil
+	}
+
+	return PassLine, fmt.Errorf("unknown bet %s", name)
+}
+
+// Bet is a wager on a craps table.
+type Bet struct {
+	kind   BetKind
+	amount float64
+	number int     // the number of a place bet, or the point of a line or come bet once it is set; 0 before
+	odds   float64 // the odds taken behind a pass or come bet, or laid behind a don't pass bet, once its point is set
+}
+
+// Settlement records how a bet was settled on a roll and how much money the table handed back for it,
+// which is the amount and odds that were bet plus any winnings, or 0 for a loss.
+type Settlement struct {
+	bet      *Bet
+	outcome  Outcome
+	returned float64
+}
+
+// Table holds the point and the bets of one player at a craps table.
+type Table struct {
+	point int // the point of the pass line, or 0 if the next roll is a come-out roll
+	bets  []*Bet
+}
+
+// ComeOut is a Table method that returns true if the next roll is a come-out roll.
+func (t *Table) ComeOut() bool {
+	return t.point == 0
+}
+
+// CanMake is a Table method that takes a kind of bet and a number, and returns an error if that bet can't be made
+// before the next roll: line bets only on come-out rolls, come bets only once there is a point, and place bets
+// only on a point number.
+func (t *Table) CanMake(kind BetKind, number int) error {
+	switch kind {
+	case PassLine, DontPassLine:
+		if !t.ComeOut() {
+			return fmt.Errorf("line bets can only be made on a come-out roll")
+		}
+	case Come:
+		if t.ComeOut() {
+			return fmt.Errorf("come bets can only be made once there is a point")
+		}
+	case Place:
+		if !IsPoint(number) {
+			return fmt.Errorf("can't place the number %d", number)
+		}
+	}
+	return nil
+}
+
+// MakeBet is a Table method that takes a kind of bet, an amount, and for a place bet its number, and puts the bet
+// on the table, returning a pointer to it. It panics if the bet can't be made; see CanMake.
+func (t *Table) MakeBet(kind BetKind, amount float64, number int) *Bet {
+	if err := t.CanMake(kind, number); err != nil {
+		panic(err)
+	}
+
+	b := &Bet{kind: kind, amount: amount}
+	if kind == Place {
+		b.number = number
+	}
+	t.bets = append(t.bets, b)
+	return b
+}
+
+// TakeOdds is a Table method that takes a line or come bet on the table whose point is set and an amount, and adds
+// that much odds behind it: taken on a pass or come bet, or laid on a don't pass bet.
+func (t *Table) TakeOdds(b *Bet, amount float64) {
+	if b.kind != PassLine && b.kind != DontPassLine && b.kind != Come {
+		panic("Error: odds can only be taken behind a pass, don't pass or come bet.")
+	}
+	if b.number == 0 {
+		panic("Error: odds can only be taken once a bet's point is set.")
+	}
+	b.odds += amount
+}
+
+// Roll is a Table method that takes the sum of a roll of two dice. It settles every bet the roll decides,
+// removes those bets from the table, moves the point, and returns the settlements.
+func (t *Table) Roll(sum int) []Settlement {
+	settlements := make([]Settlement, 0)
+	kept := make([]*Bet, 0, len(t.bets))
+
+	for _, b := range t.bets {
+		if s, settled := t.Settle(b, sum); settled {
+			settlements = append(settlements, s)
+		} else {
+			kept = append(kept, b)
+		}
+	}
+	t.bets = kept
+
+	if t.ComeOut() && IsPoint(sum) {
+		t.point = sum
+	} else if !t.ComeOut() && (sum == t.point || sum == 7) {
+		t.point = 0
+	}
+
+	return settlements
+}
+
+// Settle is a Table method that takes one of its bets and the sum of a roll, and returns how the roll settles the bet
+// along with true, or false if the bet stays on the table. A line or come bet whose point is set by the roll
+// records the point and stays.
+func (t *Table) Settle(b *Bet, sum int) (Settlement, bool) {
+	switch b.kind {
+	case PassLine, Come:
+		if b.number == 0 {
+			switch sum {
+			case 7, 11:
+				return Settlement{bet: b, outcome: Win, returned: 2 * b.amount}, true
+			case 2, 3, 12:
+				return Settlement{bet: b, outcome: Lose}, true
+			}
+			b.number = sum
+			return Settlement{}, false
+		}
+
+		// the odds behind a come bet are off on a come-out roll, and are handed back whatever happens to the bet
+		oddsWorking := b.kind == PassLine || !t.ComeOut()
+		if sum == b.number {
+			returned := 2 * b.amount
+			if oddsWorking {
+				returned += b.odds * (1 + TakeOddsPayout(b.number))
+			} else {
+				returned += b.odds
+			}
+			return Settlement{bet: b, outcome: Win, returned: returned}, true
+		} else if sum == 7 {
+			returned := 0.0
+			if !oddsWorking {
+				returned = b.odds
+			}
+			return Settlement{bet: b, outcome: Lose, returned: returned}, true
+		}
+
+	case DontPassLine:
+		if b.number == 0 {
+			switch sum {
+			case 2, 3:
+				return Settlement{bet: b, outcome: Win, returned: 2 * b.amount}, true
+			case 7, 11:
+				return Settlement{bet: b, outcome: Lose}, true
+			case 12:
+				return Settlement{bet: b, outcome: Push, returned: b.amount}, true
+			}
+			b.number = sum
+			return Settlement{}, false
+		}
+
+		if sum == 7 {
+			return Settlement{bet: b, outcome: Win, returned: 2*b.amount + b.odds*(1+LayOddsPayout(b.number))}, true
+		} else if sum == b.number {
+			return Settlement{bet: b, outcome: Lose}, true
+		}
+
+	case Field:
+		switch sum {
+		case 2:
+			return Settlement{bet: b, outcome: Win, returned: 3 * b.amount}, true
+		case 12:
+			return Settlement{bet: b, outcome: Win, returned: 4 * b.amount}, true
+		case 3, 4, 9, 10, 11:
+			return Settlement{bet: b, outcome: Win, returned: 2 * b.amount}, true
+		}
+		return Settlement{bet: b, outcome: Lose}, true
+
+	case Place:
+		// place bets are off on come-out rolls
+		if t.ComeOut() {
+			return Settlement{}, false
+		}
+		if sum == b.number {
+			return Settlement{bet: b, outcome: Win, returned: b.amount * (1 + PlacePayout(b.number))}, true
+		} else if sum == 7 {
+			return Settlement{bet: b, outcome: Lose}, true
+		}
+	}
+
+	return Settlement{}, false
+}
+
+// TakeOddsPayout takes a point and returns what odds taken behind a pass or come bet on it pay for every 1 bet,
+// which are the true odds against the point.
+func TakeOddsPayout(point int) float64 {
+	payout, _ := OddsPayout(point).Float64()
+	return payout
+}
+
+// LayOddsPayout takes a point and returns what odds laid behind a don't pass bet on it pay for every 1 bet,
+// which are the true odds of the 7 coming first: 1 to 2 on 4 and 10, 2 to 3 on 5 and 9, and 5 to 6 on 6 and 8.
+func LayOddsPayout(point int) float64 {
+	return float64(DiceSumWays(point)) / 6
+}
+
+// PlacePayout takes a point number and returns what a place bet on it pays for every 1 bet:
+// 9 to 5 on 4 and 10, 7 to 5 on 5 and 9, and 7 to 6 on 6 and 8.
+func PlacePayout(number int) float64 {
+	switch number {
+	case 4, 10:
+		return 9.0 / 5.0
+	case 5, 9:
+		return 7.0 / 5.0
+	case 6, 8:
+		return 7.0 / 6.0
+	}
+	panic("Error: place bets can only be made on 4, 5, 6, 8, 9 or 10.")
+}
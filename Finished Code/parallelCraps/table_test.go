@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// RollUntilSettled takes a Table, one of its bets, and a sequence of sums. It rolls the sums in turn and returns
+// the Settlement of the bet along with the number of rolls it took, or false if no roll settled it.
+func RollUntilSettled(t *Table, b *Bet, sums []int) (Settlement, int, bool) {
+	for i, sum := range sums {
+		for _, s := range t.Roll(sum) {
+			if s.bet == b {
+				return s, i + 1, true
+			}
+		}
+	}
+	return Settlement{}, len(sums), false
+}
+
+// TestTableSettlements tests what the table hands back for every kind of bet on fixed sequences of rolls.
+func TestTableSettlements(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     BetKind
+		amount   float64
+		number   int
+		setup    []int   // rolled before the bet is made
+		oddsAt   int     // how many rolls after the bet is made to take its odds; 0 for none
+		odds     float64 // the amount of odds taken or laid
+		sums     []int   // rolled after the bet is made
+		rolls    int     // the roll that settles the bet
+		outcome  Outcome
+		returned float64
+	}{
+		{name: "field pays double on 2", kind: Field, amount: 10, sums: []int{2}, rolls: 1, outcome: Win, returned: 30},
+		{name: "field pays triple on 12", kind: Field, amount: 10, sums: []int{12}, rolls: 1, outcome: Win, returned: 40},
+		{name: "field pays even money on 9", kind: Field, amount: 10, sums: []int{9}, rolls: 1, outcome: Win, returned: 20},
+		{name: "field loses on 7", kind: Field, amount: 10, sums: []int{7}, rolls: 1, outcome: Lose, returned: 0},
+
+		{name: "place 4 pays 9 to 5", kind: Place, amount: 5, number: 4, setup: []int{8}, sums: []int{4}, rolls: 1, outcome: Win, returned: 14},
+		{name: "place 10 pays 9 to 5", kind: Place, amount: 5, number: 10, setup: []int{8}, sums: []int{10}, rolls: 1, outcome: Win, returned: 14},
+		{name: "place 5 pays 7 to 5", kind: Place, amount: 5, number: 5, setup: []int{8}, sums: []int{5}, rolls: 1, outcome: Win, returned: 12},
+		{name: "place 9 pays 7 to 5", kind: Place, amount: 5, number: 9, setup: []int{8}, sums: []int{9}, rolls: 1, outcome: Win, returned: 12},
+		{name: "place 6 pays 7 to 6", kind: Place, amount: 6, number: 6, setup: []int{8}, sums: []int{6}, rolls: 1, outcome: Win, returned: 13},
+		{name: "place 8 pays 7 to 6", kind: Place, amount: 6, number: 8, setup: []int{4}, sums: []int{8}, rolls: 1, outcome: Win, returned: 13},
+		{name: "place loses on 7", kind: Place, amount: 6, number: 6, setup: []int{8}, sums: []int{7}, rolls: 1, outcome: Lose, returned: 0},
+		{name: "place is off on the come-out roll", kind: Place, amount: 6, number: 6, sums: []int{7, 6, 6}, rolls: 3, outcome: Win, returned: 13},
+
+		{name: "pass wins on 7 on the come-out roll", kind: PassLine, amount: 10, sums: []int{7}, rolls: 1, outcome: Win, returned: 20},
+		{name: "pass loses on 3 on the come-out roll", kind: PassLine, amount: 10, sums: []int{3}, rolls: 1, outcome: Lose, returned: 0},
+		{name: "pass odds on 4 pay 2 to 1", kind: PassLine, amount: 10, oddsAt: 1, odds: 10, sums: []int{4, 11, 4}, rolls: 3, outcome: Win, returned: 50},
+		{name: "pass odds on 5 pay 3 to 2", kind: PassLine, amount: 10, oddsAt: 1, odds: 10, sums: []int{5, 5}, rolls: 2, outcome: Win, returned: 45},
+		{name: "pass odds on 6 pay 6 to 5", kind: PassLine, amount: 10, oddsAt: 1, odds: 10, sums: []int{6, 6}, rolls: 2, outcome: Win, returned: 42},
+		{name: "pass and its odds lose on 7", kind: PassLine, amount: 10, oddsAt: 1, odds: 10, sums: []int{6, 7}, rolls: 2, outcome: Lose, returned: 0},
+
+		{name: "don't pass wins on 3", kind: DontPassLine, amount: 10, sums: []int{3}, rolls: 1, outcome: Win, returned: 20},
+		{name: "don't pass loses on 11", kind: DontPassLine, amount: 10, sums: []int{11}, rolls: 1, outcome: Lose, returned: 0},
+		{name: "don't pass pushes on 12", kind: DontPassLine, amount: 10, sums: []int{12}, rolls: 1, outcome: Push, returned: 10},
+		{name: "don't pass loses when the point is made", kind: DontPassLine, amount: 10, sums: []int{8, 8}, rolls: 2, outcome: Lose, returned: 0},
+		{name: "odds laid on 10 pay 1 to 2", kind: DontPassLine, amount: 10, oddsAt: 1, odds: 20, sums: []int{10, 7}, rolls: 2, outcome: Win, returned: 50},
+		{name: "odds laid on 9 pay 2 to 3", kind: DontPassLine, amount: 10, oddsAt: 1, odds: 15, sums: []int{9, 7}, rolls: 2, outcome: Win, returned: 45},
+		{name: "odds laid on 8 pay 5 to 6", kind: DontPassLine, amount: 10, oddsAt: 1, odds: 12, sums: []int{8, 7}, rolls: 2, outcome: Win, returned: 42},
+
+		{name: "come wins on 11 on its first roll", kind: Come, amount: 10, setup: []int{6}, sums: []int{11}, rolls: 1, outcome: Win, returned: 20},
+		{name: "come loses on 12 on its first roll", kind: Come, amount: 10, setup: []int{6}, sums: []int{12}, rolls: 1, outcome: Lose, returned: 0},
+		{name: "come odds work while there is a point", kind: Come, amount: 10, setup: []int{6}, oddsAt: 1, odds: 10, sums: []int{4, 4}, rolls: 2, outcome: Win, returned: 50},
+		// the pass line's point 6 is made on the second roll, so the come bet's point 4 is made on a come-out roll
+		{name: "come odds are off and returned when the come bet wins on the come-out roll", kind: Come, amount: 10, setup: []int{6}, oddsAt: 1, odds: 10,
+			sums: []int{4, 6, 4}, rolls: 3, outcome: Win, returned: 30},
+		{name: "come odds are off and returned when the come bet loses on the come-out roll", kind: Come, amount: 10, setup: []int{6}, oddsAt: 1, odds: 10,
+			sums: []int{4, 6, 7}, rolls: 3, outcome: Lose, returned: 10},
+		{name: "come and its odds lose on 7 while there is a point", kind: Come, amount: 10, setup: []int{6}, oddsAt: 1, odds: 10, sums: []int{4, 7}, rolls: 2, outcome: Lose, returned: 0},
+	}
+
+	for _, test := range tests {
+		var table Table
+		for _, sum := range test.setup {
+			table.Roll(sum)
+		}
+
+		b := table.MakeBet(test.kind, test.amount, test.number)
+		sums := test.sums
+		if test.oddsAt > 0 {
+			if _, _, settled := RollUntilSettled(&table, b, sums[:test.oddsAt]); settled {
+				t.Fatalf("%s: bet settled before its odds were taken", test.name)
+			}
+			table.TakeOdds(b, test.odds)
+			sums = sums[test.oddsAt:]
+		}
+
+		s, rolls, settled := RollUntilSettled(&table, b, sums)
+		rolls += test.oddsAt
+		if !settled {
+			t.Errorf("%s: bet was never settled", test.name)
+			continue
+		}
+		if rolls != test.rolls || s.outcome != test.outcome || math.Abs(s.returned-test.returned) > 1e-9 {
+			t.Errorf("%s: settled on roll %d with outcome %d returning %g, want roll %d with outcome %d returning %g",
+				test.name, rolls, s.outcome, s.returned, test.rolls, test.outcome, test.returned)
+		}
+	}
+}
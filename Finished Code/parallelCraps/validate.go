@@ -0,0 +1,80 @@
+package main
+
+//this file checks the simulated craps bets against their exact odds.
+
+import (
+	"fmt"
+	"math"
+	"montecarlo"
+	"rng"
+)
+
+// Validation holds the exact odds of a bet next to a simulated estimate of its expected payoff.
+type Validation struct {
+	bet       ExactBet
+	exact     float64
+	simulated montecarlo.Summary
+	z         float64 // how many standard errors the estimate lies from the exact value
+	ok        bool    // true if the exact value lies within the confidence interval of the estimate
+}
+
+// ValidateBet takes an ExactBet, the trial that simulates it, a number of trials and processors, a seed, and a
+// confidence level. It simulates the bet and returns how far the estimate of its expected payoff lies from the
+// exact value, counting it consistent if the exact value is inside the confidence interval at that level.
+func ValidateBet(bet ExactBet, trial montecarlo.Trial, numTrials, numProcs int, seed int64, level float64) Validation {
+	var v Validation
+	v.bet = bet
+	v.exact, _ = bet.expectedPayoff.Float64()
+	v.simulated = montecarlo.Run(trial, numTrials, numProcs, seed)
+
+	v.z = (v.simulated.Mean() - v.exact) / v.simulated.StdErr()
+	v.ok = math.Abs(v.z) <= montecarlo.ZScore(level)
+
+	return v
+}
+
+// SimulatedBet holds the exact odds of a bet together with the trial that simulates it.
+type SimulatedBet struct {
+	bet   ExactBet
+	trial montecarlo.Trial
+}
+
+// SimulatedBets returns every bet of ExactBets paired with the trial that simulates it.
+func SimulatedBets() []SimulatedBet {
+	bets := []SimulatedBet{
+		{ExactPassLine(), CrapsPassLine},
+		{ExactDontPass(), DontPass},
+		{ExactCome(), ComeBet},
+	}
+	for _, point := range Points {
+		bets = append(bets, SimulatedBet{ExactOdds(point), OddsBet(point)})
+	}
+	return bets
+}
+
+// ValidateExactBets takes a number of trials and processors, a seed, and a confidence level, and validates every bet
+// of SimulatedBets against its simulation. Each bet gets its own seed derived from seed, so the simulations are independent.
+func ValidateExactBets(numTrials, numProcs int, seed int64, level float64) []Validation {
+	bets := SimulatedBets()
+	validations := make([]Validation, len(bets))
+	for i, b := range bets {
+		validations[i] = ValidateBet(b.bet, b.trial, numTrials, numProcs, rng.DeriveSeed(seed, i), level)
+	}
+
+	return validations
+}
+
+// PrintExactBets takes a slice of Validations and prints the exact odds of every bet alongside its simulation.
+func PrintExactBets(validations []Validation) {
+	for _, v := range validations {
+		bet := v.bet
+		verdict := "consistent"
+		if !v.ok {
+			verdict = "INCONSISTENT"
+		}
+
+		fmt.Printf("%-11s win %-9s lose %-9s push %-6s pays %-4s exact payoff %-8s = %+.5f, simulated %+.5f +/- %.5f (z = %+.2f, %s)\n",
+			bet.name, bet.win.RatString(), bet.lose.RatString(), bet.push.RatString(), bet.payout.RatString(),
+			bet.expectedPayoff.RatString(), v.exact, v.simulated.Mean(), v.simulated.StdErr(), v.z, verdict)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+//this file contains the statistics of diffusion: mean squared displacement, the diffusion coefficient,
+//and concentration histograms, along with the analytic solution for particles that all start at one point.
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+)
+
+// HistogramBin holds the particles whose distance (or coordinate) falls between low and high.
+type HistogramBin struct {
+	low, high float64
+	count     int
+	density   float64 // the fraction of particles in the bin per unit area (radial) or per unit length (1-D)
+	analytic  float64 // the same density predicted by the Gaussian solution of the diffusion equation
+}
+
+// MeanSquaredDisplacement takes a slice of Boards and returns, for every Board, the mean over its particles
+// of the squared distance each particle has moved from where it started. Boards without particles get 0.
+func MeanSquaredDisplacement(boards []*Board) []float64 {
+	msd := make([]float64, len(boards))
+
+	for t, b := range boards {
+		if len(b.particles) == 0 {
+			continue
+		}
+		for _, p := range b.particles {
+			msd[t] += p.displacement.x*p.displacement.x + p.displacement.y*p.displacement.y
+		}
+		msd[t] /= float64(len(b.particles))
+	}
+
+	return msd
+}
+
+// FitDiffusionCoefficient takes the mean squared displacement at every step and returns the diffusion
+// coefficient D of the least squares line through the origin, using that MSD(t) = 4Dt in two dimensions.
+func FitDiffusionCoefficient(msd []float64) float64 {
+	numerator, denominator := 0.0, 0.0
+	for t := range msd {
+		numerator += float64(t) * msd[t]
+		denominator += float64(t) * float64(t)
+	}
+
+	if denominator == 0 {
+		return 0.0
+	}
+
+	return numerator / (4 * denominator)
+}
+
+// TheoreticalDiffusionCoefficient takes the length of a random step and returns the diffusion coefficient
+// of a walker taking one step of that length in a uniformly random direction per unit time, which is length^2 / 4.
+func TheoreticalDiffusionCoefficient(stepLength float64) float64 {
+	return stepLength * stepLength / 4
+}
+
+// GaussianDensity2D takes a distance r from the starting point, a diffusion coefficient D, and a time t.
+// It returns the density per unit area of particles that all started at one point, 1/(4 pi D t) exp(-r^2 / (4 D t)).
+func GaussianDensity2D(r, D, t float64) float64 {
+	return math.Exp(-r*r/(4*D*t)) / (4 * math.Pi * D * t)
+}
+
+// GaussianDensity1D takes a displacement x along one axis, a diffusion coefficient D, and a time t.
+// It returns the density per unit length of that coordinate, 1/sqrt(4 pi D t) exp(-x^2 / (4 D t)).
+func GaussianDensity1D(x, D, t float64) float64 {
+	return math.Exp(-x*x/(4*D*t)) / math.Sqrt(4*math.Pi*D*t)
+}
+
+// RadialHistogram is a Board method that takes a number of bins, a bin width, a diffusion coefficient D, and the time t
+// of the Board. It bins the distances of the particles from where they started into rings of the given width, and
+// compares the density in each ring with GaussianDensity2D at the ring's midpoint.
+func (b *Board) RadialHistogram(numBins int, binWidth, D, t float64) []HistogramBin {
+	bins := make([]HistogramBin, numBins)
+	for k := range bins {
+		bins[k].low = float64(k) * binWidth
+		bins[k].high = float64(k+1) * binWidth
+	}
+
+	for _, p := range b.particles {
+		r := math.Sqrt(p.displacement.x*p.displacement.x + p.displacement.y*p.displacement.y)
+		k := int(r / binWidth)
+		if k < numBins {
+			bins[k].count++
+		}
+	}
+
+	for k := range bins {
+		area := math.Pi * (bins[k].high*bins[k].high - bins[k].low*bins[k].low)
+		if len(b.particles) > 0 {
+			bins[k].density = float64(bins[k].count) / (area * float64(len(b.particles)))
+		}
+		if t > 0 {
+			bins[k].analytic = GaussianDensity2D((bins[k].low+bins[k].high)/2, D, t)
+		}
+	}
+
+	return bins
+}
+
+// ProfileHistogram is a Board method that takes a number of bins, a bin width, a diffusion coefficient D, and the time t
+// of the Board. It bins the horizontal displacements of the particles into intervals of the given width centered on 0,
+// and compares the density in each interval with GaussianDensity1D at the interval's midpoint.
+func (b *Board) ProfileHistogram(numBins int, binWidth, D, t float64) []HistogramBin {
+	bins := make([]HistogramBin, numBins)
+	offset := float64(numBins) * binWidth / 2
+	for k := range bins {
+		bins[k].low = float64(k)*binWidth - offset
+		bins[k].high = float64(k+1)*binWidth - offset
+	}
+
+	for _, p := range b.particles {
+		k := int(math.Floor((p.displacement.x + offset) / binWidth))
+		if k >= 0 && k < numBins {
+			bins[k].count++
+		}
+	}
+
+	for k := range bins {
+		if len(b.particles) > 0 {
+			bins[k].density = float64(bins[k].count) / (binWidth * float64(len(b.particles)))
+		}
+		if t > 0 {
+			bins[k].analytic = GaussianDensity1D((bins[k].low+bins[k].high)/2, D, t)
+		}
+	}
+
+	return bins
+}
+
+// WriteMSDToFile takes the mean squared displacement at every step, a fitted diffusion coefficient, and a file name.
+// It writes one line per step to a CSV file, giving the measured MSD and the fitted line 4Dt.
+func WriteMSDToFile(msd []float64, D float64, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, "step,msd,fit")
+	for t := range msd {
+		fmt.Fprintf(writer, "%d,%g,%g\n", t, msd[t], 4*D*float64(t))
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// WriteHistogramsToFile takes a slice of the steps that were sampled, the histogram at each of them, and a file name.
+// It writes one line per bin of every histogram to a CSV file.
+func WriteHistogramsToFile(steps []int, histograms [][]HistogramBin, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, "step,low,high,count,density,analytic")
+	for i, step := range steps {
+		for _, bin := range histograms[i] {
+			fmt.Fprintf(writer, "%d,%g,%g,%d,%g,%g\n", step, bin.low, bin.high, bin.count, bin.density, bin.analytic)
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AnalyzeDiffusion takes a slice of Boards, the length of a random step, a sampling frequency, a number of bins, and
+// a bin width. It writes the mean squared displacement to msd.csv and the radial and 1-D histograms of every
+// frequency-th Board to radial.csv and profile.csv, with the analytic Gaussian solution alongside, and returns the
+// fitted and theoretical diffusion coefficients. The Gaussian uses the theoretical coefficient, so it is exact
+// for particles that all start at one point, as InitializeBoard places them when random is false, and that
+// haven't yet felt the boundary, walls, or each other.
+func AnalyzeDiffusion(boards []*Board, stepLength float64, frequency, numBins int, binWidth float64) (float64, float64) {
+	msd := MeanSquaredDisplacement(boards)
+	fitted := FitDiffusionCoefficient(msd)
+	theoretical := TheoreticalDiffusionCoefficient(stepLength)
+
+	WriteMSDToFile(msd, fitted, "msd.csv")
+
+	steps := make([]int, 0)
+	radial := make([][]HistogramBin, 0)
+	profile := make([][]HistogramBin, 0)
+
+	for t := frequency; t < len(boards); t += frequency {
+		steps = append(steps, t)
+		radial = append(radial, boards[t].RadialHistogram(numBins, binWidth, theoretical, float64(t)))
+		profile = append(profile, boards[t].ProfileHistogram(numBins, binWidth, theoretical, float64(t)))
+	}
+
+	WriteHistogramsToFile(steps, radial, "radial.csv")
+	WriteHistogramsToFile(steps, profile, "profile.csv")
+
+	return fitted, theoretical
+}
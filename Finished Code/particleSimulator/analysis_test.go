@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMeanSquaredDisplacement tests the mean squared displacement of boards whose displacements are set by hand,
+// including a board without particles.
+func TestMeanSquaredDisplacement(t *testing.T) {
+	b := InitializeBoard(10, 10, 3, 1, 1, false, 1)
+	b.particles[0].displacement = OrderedPair{3, 4}
+	b.particles[2].displacement = OrderedPair{-1, 1}
+	empty := InitializeBoard(10, 10, 0, 1, 1, false, 1)
+
+	msd := MeanSquaredDisplacement([]*Board{b, empty})
+	if len(msd) != 2 {
+		t.Fatalf("got %d values for 2 boards", len(msd))
+	}
+	if math.Abs(msd[0]-9) > 1e-12 {
+		t.Errorf("MSD = %g, want (25 + 0 + 2) / 3 = 9", msd[0])
+	}
+	if msd[1] != 0 {
+		t.Errorf("MSD of an empty board = %g, want 0", msd[1])
+	}
+}
+
+// TestFitDiffusionCoefficient tests that the fit recovers D exactly from an MSD of 4Dt, and returns 0
+// when there is nothing to fit.
+func TestFitDiffusionCoefficient(t *testing.T) {
+	for _, D := range []float64{0.25, 0.3, 7} {
+		msd := make([]float64, 51)
+		for step := range msd {
+			msd[step] = 4 * D * float64(step)
+		}
+		if got := FitDiffusionCoefficient(msd); math.Abs(got-D) > 1e-12 {
+			t.Errorf("FitDiffusionCoefficient of 4 * %g * t = %g, want %g", D, got, D)
+		}
+	}
+
+	if got := FitDiffusionCoefficient([]float64{0}); got != 0 {
+		t.Errorf("FitDiffusionCoefficient of a single step = %g, want 0", got)
+	}
+}
+
+// TestHistogramsMatchGaussian tests particles that all start at the center of an unbounded board. After many steps,
+// the fitted diffusion coefficient matches the theoretical one, the radial and 1-D densities integrate to about 1,
+// and the count in every bin agrees with the Gaussian solution within Poisson error.
+func TestHistogramsMatchGaussian(t *testing.T) {
+	numParticles, numSteps := 3000, 100
+	stepLength := 1.0
+	D := TheoreticalDiffusionCoefficient(stepLength)
+
+	b := InitializeBoard(1000, 1000, numParticles, 0.1, stepLength, false, 1)
+	b.boundary = Unbounded
+	boards := UpdateBoards(b, numSteps, false, 1)
+
+	if fitted := FitDiffusionCoefficient(MeanSquaredDisplacement(boards)); math.Abs(fitted-D) > 0.05*D {
+		t.Errorf("fitted diffusion coefficient %g, want %g", fitted, D)
+	}
+
+	// 4Dt = 100, so the particles spread about 7 units along each axis; the bins reach past 3.5 times that
+	last := boards[numSteps]
+	tests := []struct {
+		name  string
+		bins  []HistogramBin
+		width func(bin HistogramBin) float64 // the area or length of a bin
+	}{
+		{"radial", last.RadialHistogram(25, 1, D, float64(numSteps)), func(bin HistogramBin) float64 {
+			return math.Pi * (bin.high*bin.high - bin.low*bin.low)
+		}},
+		{"profile", last.ProfileHistogram(50, 1, D, float64(numSteps)), func(bin HistogramBin) float64 {
+			return bin.high - bin.low
+		}},
+	}
+
+	for _, test := range tests {
+		total := 0.0
+		for _, bin := range test.bins {
+			size := test.width(bin)
+			total += bin.density * size
+
+			expected := bin.analytic * size * float64(numParticles)
+			if math.Abs(float64(bin.count)-expected) > 4*math.Sqrt(expected)+1 {
+				t.Errorf("%s bin [%g, %g): %d particles, want about %.1f from the Gaussian", test.name, bin.low, bin.high, bin.count, expected)
+			}
+		}
+
+		if math.Abs(total-1) > 0.01 {
+			t.Errorf("%s density integrates to %g, want about 1", test.name, total)
+		}
+	}
+}
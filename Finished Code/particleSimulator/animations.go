@@ -31,6 +31,21 @@ func (b *Board) DrawToCanvas(canvasWidth int) image.Image {
 	c.ClearRect(0, 0, canvasWidth, canvasWidth)
 	c.Fill()
 
+	// draw walls in gray and membranes in light blue
+	for _, w := range b.walls {
+		scalingFactor := float64(canvasHeight) / b.height
+
+		if w.permeability > 0 {
+			c.SetStrokeColor(canvas.MakeColor(120, 180, 255))
+		} else {
+			c.SetStrokeColor(canvas.MakeColor(150, 150, 150))
+		}
+		c.SetLineWidth(2)
+		c.MoveTo(w.start.x*scalingFactor, w.start.y*scalingFactor)
+		c.LineTo(w.end.x*scalingFactor, w.end.y*scalingFactor)
+		c.Stroke()
+	}
+
 	for _, p := range b.particles {
 		// make a circle at p's position with the appropriate width
 		scalingFactor := float64(canvasHeight) / b.height
@@ -0,0 +1,239 @@
+package main
+
+//this file contains the boundary conditions at the edges of the board and the walls inside it.
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Boundary says what happens to a particle that steps off the edge of the board.
+type Boundary int
+
+const (
+	Unbounded  Boundary = iota // particles keep walking off the visible board
+	Reflecting                 // particles bounce back off the edges
+	Absorbing                  // particles are removed and counted
+	Periodic                   // particles leaving one edge come back through the opposite edge
+	Sticky                     // particles stop at the edge and never move again
+)
+
+// Fate says what became of a particle after a step.
+type Fate int
+
+const (
+	Moved Fate = iota
+	Stuck
+	Absorbed
+)
+
+// BoundaryByName takes the name of a boundary condition ("unbounded", "reflecting", "absorbing", "periodic",
+// or "sticky") and returns that Boundary.
+func BoundaryByName(name string) (Boundary, error) {
+	switch name {
+	case "unbounded":
+		return Unbounded, nil
+	case "reflecting":
+		return Reflecting, nil
+	case "absorbing":
+		return Absorbing, nil
+	case "periodic":
+		return Periodic, nil
+	case "sticky":
+		return Sticky, nil
+	}
+
+	return Unbounded, fmt.Errorf("unknown boundary %s", name)
+}
+
+// ResolveStep is a Board method that takes the position of a particle, the position it proposes to step to,
+// and a random stream for any membranes the step crosses. It returns where the particle actually ends up, along with its Fate. A step that crosses a wall, or a membrane
+// that doesn't let it through, is cancelled. A step that leaves the board is then handled by the boundary.
+// The particle itself is not changed.
+func (b *Board) ResolveStep(position, proposal OrderedPair, r *rand.Rand) (OrderedPair, Fate) {
+	if b.Blocked(position, proposal, r) {
+		return position, Moved
+	}
+
+	if proposal.x >= 0 && proposal.x <= b.width && proposal.y >= 0 && proposal.y <= b.height {
+		return proposal, Moved
+	}
+
+	switch b.boundary {
+	case Periodic:
+		return OrderedPair{x: Wrap(proposal.x, b.width), y: Wrap(proposal.y, b.height)}, Moved
+	case Absorbing:
+		return proposal, Absorbed
+	case Reflecting, Sticky:
+		var final OrderedPair
+		fate := Moved
+		if b.boundary == Reflecting {
+			final = OrderedPair{x: Reflect(proposal.x, b.width), y: Reflect(proposal.y, b.height)}
+		} else {
+			final = OrderedPair{x: math.Max(0, math.Min(b.width, proposal.x)), y: math.Max(0, math.Min(b.height, proposal.y))}
+			fate = Stuck
+		}
+
+		// a wall that meets the edge must not be slipped around by bouncing off the edge beyond its end
+		if b.Blocked(position, final, r) {
+			return position, Moved
+		}
+		return final, fate
+	}
+
+	return proposal, Moved
+}
+
+// ApplyFate is a Particle method that takes the Fate of its last step and records it.
+func (p *Particle) ApplyFate(fate Fate) {
+	if fate == Stuck {
+		p.stuck = true
+	} else if fate == Absorbed {
+		p.absorbed = true
+	}
+}
+
+// Reflect takes a coordinate and the length of the board along it, and mirrors the coordinate back
+// across the edges until it lies between 0 and length.
+func Reflect(x, length float64) float64 {
+	x = math.Mod(x, 2*length)
+	if x < 0 {
+		x += 2 * length
+	}
+	if x > length {
+		x = 2*length - x
+	}
+	return x
+}
+
+// Wrap takes a coordinate and the length of the board along it, and returns the coordinate modulo length.
+func Wrap(x, length float64) float64 {
+	x = math.Mod(x, length)
+	if x < 0 {
+		x += length
+	}
+	return x
+}
+
+// RemoveAbsorbed is a Board method that removes the particles that left through an absorbing boundary
+// and adds them to the count of absorbed particles.
+func (b *Board) RemoveAbsorbed() {
+	kept := b.particles[:0]
+	for _, p := range b.particles {
+		if p.absorbed {
+			b.absorbed++
+		} else {
+			kept = append(kept, p)
+		}
+	}
+
+	// clear the tail so that removed particles can be garbage collected
+	for i := len(kept); i < len(b.particles); i++ {
+		b.particles[i] = nil
+	}
+
+	b.particles = kept
+}
+
+// Blocked is a Board method that takes the start and end of a step and a random stream, and returns true if the step
+// is stopped by one of the walls. A solid wall always stops a crossing step, while a membrane lets it through with
+// probability equal to its permeability, drawn from r independently for each membrane crossed.
+func (b *Board) Blocked(start, end OrderedPair, r *rand.Rand) bool {
+	for _, w := range b.walls {
+		if SegmentsCross(start, end, w.start, w.end) {
+			if w.permeability <= 0 || r.Float64() >= w.permeability {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SegmentsCross takes the endpoints of two line segments and returns true if the segments intersect.
+func SegmentsCross(p1, p2, q1, q2 OrderedPair) bool {
+	d1 := Orientation(q1, q2, p1)
+	d2 := Orientation(q1, q2, p2)
+	d3 := Orientation(p1, p2, q1)
+	d4 := Orientation(p1, p2, q2)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	// a step ending exactly on a wall, or passing through one of its ends, touches it;
+	// a step starting on a wall doesn't, so that a particle placed on a wall can still leave it
+	return (d2 == 0 && OnSegment(q1, q2, p2)) || (d3 == 0 && OnSegment(p1, p2, q1)) || (d4 == 0 && OnSegment(p1, p2, q2))
+}
+
+// Orientation takes three points and returns the cross product of b - a and c - a, which is positive if
+// the points turn counterclockwise, negative if they turn clockwise, and zero if they are collinear.
+func Orientation(a, b, c OrderedPair) float64 {
+	return (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+}
+
+// OnSegment takes the endpoints of a segment and a point collinear with it, and returns true if the point
+// lies within the segment.
+func OnSegment(a, b, c OrderedPair) bool {
+	return c.x >= math.Min(a.x, b.x) && c.x <= math.Max(a.x, b.x) && c.y >= math.Min(a.y, b.y) && c.y <= math.Max(a.y, b.y)
+}
+
+// ReadWallsFromFile takes the name of a file and returns the walls it describes.
+// Blank lines and lines starting with # are ignored. Every other line holds the endpoints of a wall
+// followed by an optional permeability between 0 and 1, which defaults to 0 for a solid wall:
+//    x1 y1 x2 y2 [permeability]
+func ReadWallsFromFile(filename string) ([]Wall, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open wall file %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	walls := make([]Wall, 0)
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 4 && len(fields) != 5 {
+			return nil, fmt.Errorf("line %d of %s should hold 4 or 5 numbers, got %q", lineNumber, filename, line)
+		}
+
+		values := make([]float64, len(fields))
+		for i := range fields {
+			values[i], err = strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad number %q on line %d of %s", fields[i], lineNumber, filename)
+			}
+		}
+
+		var w Wall
+		w.start = OrderedPair{x: values[0], y: values[1]}
+		w.end = OrderedPair{x: values[2], y: values[3]}
+		if len(values) == 5 {
+			w.permeability = values[4]
+		}
+
+		if w.permeability < 0 || w.permeability > 1 {
+			return nil, fmt.Errorf("permeability on line %d of %s must be between 0 and 1", lineNumber, filename)
+		}
+
+		walls = append(walls, w)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read wall file %s: %v", filename, err)
+	}
+
+	return walls, nil
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"rng"
+	"testing"
+)
+
+// WriteTempFile takes a directory, a file name, and the contents of a file, writes the file, and returns its path.
+func WriteTempFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	filename := filepath.Join(dir, name)
+	if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+		t.Fatalf("couldn't write %s: %v", filename, err)
+	}
+	return filename
+}
+
+// TestReflectAndWrap tests that Reflect mirrors coordinates back onto a board of length 10 and Wrap takes them
+// modulo 10, however far off the board they start.
+func TestReflectAndWrap(t *testing.T) {
+	tests := []struct {
+		x, reflected, wrapped float64
+	}{
+		{5, 5, 5},
+		{0, 0, 0},
+		{10, 10, 0},
+		{-3, 3, 7},
+		{13, 7, 3},
+		{27, 7, 7},
+		{-25, 5, 5},
+	}
+
+	for _, test := range tests {
+		if got := Reflect(test.x, 10); math.Abs(got-test.reflected) > 1e-12 {
+			t.Errorf("Reflect(%g, 10) = %g, want %g", test.x, got, test.reflected)
+		}
+		if got := Wrap(test.x, 10); math.Abs(got-test.wrapped) > 1e-12 {
+			t.Errorf("Wrap(%g, 10) = %g, want %g", test.x, got, test.wrapped)
+		}
+	}
+
+	r := rng.New(1)
+	for i := 0; i < 1000; i++ {
+		x := (r.Float64() - 0.5) * 1000
+		if got := Reflect(x, 10); got < 0 || got > 10 {
+			t.Errorf("Reflect(%g, 10) = %g, off the board", x, got)
+		}
+		if got := Wrap(x, 10); got < 0 || got >= 10 {
+			t.Errorf("Wrap(%g, 10) = %g, off the board", x, got)
+		}
+	}
+}
+
+// TestResolveStep tests where a step off a 10 x 10 board ends up under each boundary, and that a step
+// staying on the board is never changed.
+func TestResolveStep(t *testing.T) {
+	tests := []struct {
+		boundary Boundary
+		position OrderedPair
+		proposal OrderedPair
+		want     OrderedPair
+		wantFate Fate
+	}{
+		{Unbounded, OrderedPair{9.5, 5}, OrderedPair{11, 5}, OrderedPair{11, 5}, Moved},
+		{Reflecting, OrderedPair{9.5, 5}, OrderedPair{11, 5}, OrderedPair{9, 5}, Moved},
+		{Reflecting, OrderedPair{0.5, 0.5}, OrderedPair{-1, -2}, OrderedPair{1, 2}, Moved},
+		{Periodic, OrderedPair{9.5, 5}, OrderedPair{11, 5}, OrderedPair{1, 5}, Moved},
+		{Periodic, OrderedPair{0.5, 0.5}, OrderedPair{-1, -2}, OrderedPair{9, 8}, Moved},
+		{Absorbing, OrderedPair{9.5, 5}, OrderedPair{11, 5}, OrderedPair{11, 5}, Absorbed},
+		{Sticky, OrderedPair{9.5, 5}, OrderedPair{11, 5}, OrderedPair{10, 5}, Stuck},
+		{Sticky, OrderedPair{0.5, 0.5}, OrderedPair{-1, -2}, OrderedPair{0, 0}, Stuck},
+	}
+
+	r := rng.New(1)
+	for _, test := range tests {
+		b := &Board{width: 10, height: 10, boundary: test.boundary}
+
+		got, fate := b.ResolveStep(test.position, test.proposal, r)
+		if math.Abs(got.x-test.want.x) > 1e-12 || math.Abs(got.y-test.want.y) > 1e-12 || fate != test.wantFate {
+			t.Errorf("boundary %d: step from %v to %v ended at %v with fate %d, want %v with fate %d",
+				test.boundary, test.position, test.proposal, got, fate, test.want, test.wantFate)
+		}
+
+		inside := OrderedPair{x: 5, y: 5}
+		if got, fate := b.ResolveStep(test.position, inside, r); got != inside || fate != Moved {
+			t.Errorf("boundary %d: step on the board from %v to %v ended at %v with fate %d",
+				test.boundary, test.position, inside, got, fate)
+		}
+	}
+}
+
+// TestBoundariesOverTime tests particles diffusing from the center of a small board for many steps: reflecting and
+// periodic boundaries keep every particle on the board, an absorbing boundary removes particles and counts every
+// one of them, and a sticky boundary freezes particles at the edge for good.
+func TestBoundariesOverTime(t *testing.T) {
+	numParticles, numSteps := 100, 50
+
+	for _, boundary := range []Boundary{Reflecting, Periodic, Absorbing, Sticky} {
+		b := InitializeBoard(10, 10, numParticles, 0.1, 2.0, false, 1)
+		b.boundary = boundary
+		boards := UpdateBoards(b, numSteps, false, 1)
+
+		for step, board := range boards {
+			if len(board.particles)+board.absorbed != numParticles {
+				t.Fatalf("boundary %d, step %d: %d particles and %d absorbed, want %d in all",
+					boundary, step, len(board.particles), board.absorbed, numParticles)
+			}
+			for i, p := range board.particles {
+				if p.position.x < 0 || p.position.x > 10 || p.position.y < 0 || p.position.y > 10 {
+					t.Fatalf("boundary %d, step %d: particle %d is off the board at %v", boundary, step, i, p.position)
+				}
+				if step > 0 && board.particles[i].stuck && boards[step-1].particles[i].stuck &&
+					p.position != boards[step-1].particles[i].position {
+					t.Fatalf("boundary %d, step %d: stuck particle %d moved", boundary, step, i)
+				}
+			}
+		}
+
+		last := boards[numSteps]
+		switch boundary {
+		case Absorbing:
+			if last.absorbed == 0 {
+				t.Errorf("absorbing boundary: no particle was absorbed")
+			}
+		case Sticky:
+			stuck := 0
+			for _, p := range last.particles {
+				if p.stuck {
+					stuck++
+					if p.position.x != 0 && p.position.x != 10 && p.position.y != 0 && p.position.y != 10 {
+						t.Errorf("sticky boundary: particle stuck away from the edge at %v", p.position)
+					}
+				}
+			}
+			if stuck == 0 {
+				t.Errorf("sticky boundary: no particle got stuck")
+			}
+		default:
+			if last.absorbed != 0 {
+				t.Errorf("boundary %d: %d particles absorbed, want 0", boundary, last.absorbed)
+			}
+		}
+	}
+}
+
+// TestRemoveAbsorbed tests that RemoveAbsorbed removes exactly the absorbed particles, in order, and adds them
+// to the count.
+func TestRemoveAbsorbed(t *testing.T) {
+	b := InitializeBoard(10, 10, 5, 1, 1, false, 1)
+	b.absorbed = 3
+	kept := []*Particle{b.particles[0], b.particles[2], b.particles[4]}
+	b.particles[1].ApplyFate(Absorbed)
+	b.particles[3].ApplyFate(Absorbed)
+
+	b.RemoveAbsorbed()
+
+	if b.absorbed != 5 {
+		t.Errorf("absorbed count = %d, want 5", b.absorbed)
+	}
+	if len(b.particles) != len(kept) {
+		t.Fatalf("%d particles left, want %d", len(b.particles), len(kept))
+	}
+	for i := range kept {
+		if b.particles[i] != kept[i] {
+			t.Errorf("particle %d is not the one expected", i)
+		}
+	}
+}
+
+// TestSegmentsCross tests crossing, touching, parallel and separate segments.
+func TestSegmentsCross(t *testing.T) {
+	wallStart, wallEnd := OrderedPair{5, 0}, OrderedPair{5, 10}
+
+	tests := []struct {
+		name       string
+		start, end OrderedPair
+		want       bool
+	}{
+		{"crossing", OrderedPair{4, 5}, OrderedPair{6, 5}, true},
+		{"ending on the wall", OrderedPair{4, 5}, OrderedPair{5, 5}, true},
+		{"through an end of the wall", OrderedPair{4, 9}, OrderedPair{6, 11}, true},
+		{"starting on the wall", OrderedPair{5, 5}, OrderedPair{6, 5}, false},
+		{"short of the wall", OrderedPair{3, 5}, OrderedPair{4, 5}, false},
+		{"past the end of the wall", OrderedPair{4, 11}, OrderedPair{6, 11}, false},
+		{"parallel to the wall", OrderedPair{4, 2}, OrderedPair{4, 8}, false},
+	}
+
+	for _, test := range tests {
+		if got := SegmentsCross(test.start, test.end, wallStart, wallEnd); got != test.want {
+			t.Errorf("%s: SegmentsCross = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+// TestBlocked tests that a solid wall stops every crossing step, that a membrane of permeability 1 stops none,
+// that a membrane of permeability one half stops about half, and that no wall stops a step that doesn't cross it.
+func TestBlocked(t *testing.T) {
+	start, end := OrderedPair{4, 5}, OrderedPair{6, 5}
+	beside := OrderedPair{4, 6}
+	numTrials := 10000
+
+	tests := []struct {
+		permeability float64
+		wantLow      float64 // bounds on the fraction of crossing steps blocked
+		wantHigh     float64
+	}{
+		{0, 1, 1},
+		{1, 0, 0},
+		{0.5, 0.47, 0.53},
+	}
+
+	r := rng.New(1)
+	for _, test := range tests {
+		b := &Board{width: 10, height: 10, boundary: Reflecting}
+		b.walls = []Wall{{start: OrderedPair{5, 0}, end: OrderedPair{5, 10}, permeability: test.permeability}}
+
+		blocked := 0
+		for i := 0; i < numTrials; i++ {
+			if b.Blocked(start, end, r) {
+				blocked++
+			}
+			if b.Blocked(start, beside, r) {
+				t.Fatalf("permeability %g: a step that doesn't cross the wall was blocked", test.permeability)
+			}
+		}
+
+		fraction := float64(blocked) / float64(numTrials)
+		if fraction < test.wantLow || fraction > test.wantHigh {
+			t.Errorf("permeability %g: blocked %g of crossing steps, want between %g and %g",
+				test.permeability, fraction, test.wantLow, test.wantHigh)
+		}
+
+		// a blocked step leaves the particle where it was
+		if got, fate := b.ResolveStep(start, end, rng.New(2)); test.permeability == 0 && (got != start || fate != Moved) {
+			t.Errorf("solid wall: step ended at %v with fate %d, want %v", got, fate, start)
+		}
+	}
+
+	// a step that passes beyond the end of a wall at the edge of the board must not reflect back around it
+	b := &Board{width: 10, height: 10, boundary: Reflecting}
+	b.walls = []Wall{{start: OrderedPair{5, 0}, end: OrderedPair{5, 10}}}
+	position := OrderedPair{4.8, 9.9}
+	if got, _ := b.ResolveStep(position, OrderedPair{5.5, 10.6}, r); got != position {
+		t.Errorf("step reflected around the end of a wall to %v, want it to stay at %v", got, position)
+	}
+}
+
+// TestReadWallsFromFile tests that ReadWallsFromFile reads walls and membranes, skipping comments and blank lines,
+// and returns an error for every kind of malformed wall file.
+func TestReadWallsFromFile(t *testing.T) {
+	dir := t.TempDir()
+
+	walls, err := ReadWallsFromFile(WriteTempFile(t, dir, "good.txt", "# a wall and a membrane\n\n0 1 2 3\n  4 5 6 7 0.25  \n1e2 0 100 -1.5 1\n"))
+	if err != nil {
+		t.Fatalf("couldn't read a good wall file: %v", err)
+	}
+	want := []Wall{
+		{start: OrderedPair{0, 1}, end: OrderedPair{2, 3}, permeability: 0},
+		{start: OrderedPair{4, 5}, end: OrderedPair{6, 7}, permeability: 0.25},
+		{start: OrderedPair{100, 0}, end: OrderedPair{100, -1.5}, permeability: 1},
+	}
+	if len(walls) != len(want) {
+		t.Fatalf("read %d walls, want %d", len(walls), len(want))
+	}
+	for i := range want {
+		if walls[i] != want[i] {
+			t.Errorf("wall %d = %v, want %v", i, walls[i], want[i])
+		}
+	}
+
+	bad := map[string]string{
+		"too few numbers":       "0 1 2\n",
+		"too many numbers":      "0 1 2 3 0.5 6\n",
+		"not a number":          "0 1 x 3\n",
+		"negative permeability": "0 1 2 3 -0.1\n",
+		"permeability above 1":  "0 1 2 3 1.5\n",
+	}
+	for name, contents := range bad {
+		if _, err := ReadWallsFromFile(WriteTempFile(t, dir, "bad.txt", contents)); err == nil {
+			t.Errorf("%s: ReadWallsFromFile returned no error for %q", name, contents)
+		}
+	}
+
+	if _, err := ReadWallsFromFile(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Errorf("ReadWallsFromFile returned no error for a missing file")
+	}
+
+	// the example that comes with the simulator
+	walls, err = ReadWallsFromFile(filepath.Join("walls", "channel.txt"))
+	if err != nil {
+		t.Fatalf("couldn't read walls/channel.txt: %v", err)
+	}
+	if len(walls) != 3 || walls[2].permeability != 0.1 {
+		t.Errorf("walls/channel.txt gave %v, want two solid walls and a membrane of permeability 0.1", walls)
+	}
+}
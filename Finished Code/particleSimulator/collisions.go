@@ -0,0 +1,248 @@
+package main
+
+//this file contains the hard disk version of diffusion, in which particles cannot overlap.
+
+import (
+	"math"
+	"rng"
+	"workpool"
+)
+
+// CellGrid is a spatial hash that buckets points into square cells, so that the points near a position
+// can be found without comparing against every point on the board.
+type CellGrid struct {
+	cellSize float64
+	cells    map[[2]int][]int // indices of the points in each cell
+	points   []OrderedPair
+}
+
+// MakeCellGrid takes a slice of points and a cell size, and returns a CellGrid holding the points.
+func MakeCellGrid(points []OrderedPair, cellSize float64) CellGrid {
+	g := CellGrid{
+		cellSize: cellSize,
+		cells:    make(map[[2]int][]int),
+		points:   points,
+	}
+
+	for i, pos := range points {
+		key := g.CellOf(pos)
+		g.cells[key] = append(g.cells[key], i)
+	}
+
+	return g
+}
+
+// CellOf is a CellGrid method that takes a position and returns the cell that holds it.
+func (g CellGrid) CellOf(pos OrderedPair) [2]int {
+	return [2]int{int(math.Floor(pos.x / g.cellSize)), int(math.Floor(pos.y / g.cellSize))}
+}
+
+// Near is a CellGrid method that takes a position and returns the indices of all points in the 3 x 3 block of cells
+// around it. Every point within one cell size of the position is included.
+func (g CellGrid) Near(pos OrderedPair) []int {
+	key := g.CellOf(pos)
+	near := make([]int, 0)
+
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			near = append(near, g.cells[[2]int{key[0] + dx, key[1] + dy}]...)
+		}
+	}
+
+	return near
+}
+
+// Distance takes two OrderedPairs and returns the distance between them.
+func Distance(p1, p2 OrderedPair) float64 {
+	return math.Sqrt((p1.x-p2.x)*(p1.x-p2.x) + (p1.y-p2.y)*(p1.y-p2.y))
+}
+
+// Separation is a Board method that takes two positions and returns the distance between them. Under a periodic
+// boundary, this is the distance to the nearest copy of the second position across the edges of the board.
+func (b *Board) Separation(p1, p2 OrderedPair) float64 {
+	dx, dy := p1.x-p2.x, p1.y-p2.y
+	if b.boundary == Periodic {
+		dx -= b.width * math.Round(dx/b.width)
+		dy -= b.height * math.Round(dy/b.height)
+	}
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// MaxRadius is a Board method that returns the largest radius of any of its particles.
+func (b *Board) MaxRadius() float64 {
+	max := 0.0
+	for _, p := range b.particles {
+		max = math.Max(max, p.radius)
+	}
+	return max
+}
+
+// DiffuseHardDisks is a Board method that takes a number of processors and diffuses every particle one time step,
+// treating particles as hard disks. Every particle first proposes a random step. A proposed step is then
+// rejected, leaving the particle where it is, if the disk at its new position would overlap any other particle
+// at either its old or its proposed position. Checking against both means that whichever of its neighbors'
+// moves are accepted, no two disks overlap afterward, and every particle can be checked independently.
+// Steps are first resolved against the walls and the boundary, so only where a particle actually ends up is checked.
+// Both phases divide the particles over numProcs workers, each proposing steps from its own random stream, and since the checks only read the shared positions,
+// collisions between particles in different chunks are found just as within a chunk.
+// Steps are assumed to be short compared with the radius, so disks don't jump over each other.
+func (b *Board) DiffuseHardDisks(numProcs int) {
+	n := len(b.particles)
+	if n == 0 {
+		return
+	}
+
+	// points[i] is the old position of particle i, and points[n+i] is its proposed position
+	points := make([]OrderedPair, 2*n)
+	accepted := make([]bool, n)
+
+	fates := make([]Fate, n)
+
+	streams := b.Streams(numProcs)
+
+	workpool.For(n, numProcs, func(worker, start, end int) {
+		r := streams[worker]
+		for i := start; i < end; i++ {
+			p := b.particles[i]
+			points[i] = p.position
+			if p.stuck {
+				points[n+i] = p.position
+			} else {
+				points[n+i], fates[i] = b.ResolveStep(p.position, p.ProposeStep(r), r)
+			}
+		}
+	})
+
+	// any overlapping pair of disks is closer than twice the largest radius
+	reach := 2 * b.MaxRadius()
+	grid := MakeCellGrid(points, math.Max(reach, 1e-9))
+
+	workpool.For(n, numProcs, func(worker, start, end int) {
+		for i := start; i < end; i++ {
+			accepted[i] = true
+
+			// a particle leaving the board can't collide with anything
+			if fates[i] == Absorbed {
+				continue
+			}
+
+			for _, image := range b.Images(points[n+i], reach) {
+				for _, k := range grid.Near(image) {
+					j := k % n
+					if j != i && Distance(image, points[k]) < b.particles[i].radius+b.particles[j].radius {
+						accepted[i] = false
+						break
+					}
+				}
+				if !accepted[i] {
+					break
+				}
+			}
+		}
+	})
+
+	for i, p := range b.particles {
+		if accepted[i] {
+			b.MoveParticle(p, points[n+i])
+			p.ApplyFate(fates[i])
+		}
+	}
+
+	b.RemoveAbsorbed()
+}
+
+// Images is a Board method that takes a position and a distance reach. It returns the position together with,
+// under a periodic boundary, its copies shifted by the width and height of the board that lie within reach
+// of the board, so that disks can collide across the edges.
+func (b *Board) Images(pos OrderedPair, reach float64) []OrderedPair {
+	images := []OrderedPair{pos}
+	if b.boundary != Periodic {
+		return images
+	}
+
+	xShifts := []float64{0}
+	if pos.x < reach {
+		xShifts = append(xShifts, b.width)
+	}
+	if pos.x > b.width-reach {
+		xShifts = append(xShifts, -b.width)
+	}
+
+	yShifts := []float64{0}
+	if pos.y < reach {
+		yShifts = append(yShifts, b.height)
+	}
+	if pos.y > b.height-reach {
+		yShifts = append(yShifts, -b.height)
+	}
+
+	for _, dx := range xShifts {
+		for _, dy := range yShifts {
+			if dx != 0 || dy != 0 {
+				images = append(images, OrderedPair{x: pos.x + dx, y: pos.y + dy})
+			}
+		}
+	}
+
+	return images
+}
+
+// InitializeHardDiskBoard takes the same parameters as InitializeBoard, including the seed, and returns a Board of
+// hard disks that do not overlap, placed by PlaceHardDisks.
+func InitializeHardDiskBoard(boardWidth, boardHeight float64, numParticles int, particleRadius float64, diffusionRate float64, random bool, seed int64) *Board {
+	b := InitializeBoard(boardWidth, boardHeight, numParticles, particleRadius, diffusionRate, random, seed)
+	b.hardDisks = true
+	b.PlaceHardDisks(random, seed)
+	return b
+}
+
+// PlaceHardDisks is a Board method that takes a boolean random and a seed, and moves its particles so that no two
+// of them overlap. If random is true, particles are placed at uniformly random positions drawn from the placement
+// stream of the seed, rejecting any that would overlap a particle already placed. Otherwise, they are packed on a
+// square lattice, one largest diameter apart, filling a square around the center of the board. Under a periodic
+// boundary, overlaps across the edges count too, so the boundary should be set before the disks are placed.
+// It panics if the particles don't fit.
+func (b *Board) PlaceHardDisks(random bool, seed int64) {
+	if random {
+		// start the placement stream over, since the board's initializer already drew from it
+		placement := rng.New(rng.DeriveSeed(seed, placementStream))
+		placed := make([]*Particle, 0, len(b.particles))
+
+		for _, p := range b.particles {
+			ok := false
+			for attempt := 0; attempt < 10000 && !ok; attempt++ {
+				p.position = OrderedPair{x: placement.Float64() * b.width, y: placement.Float64() * b.height}
+				ok = true
+				for _, q := range placed {
+					if b.Separation(p.position, q.position) < p.radius+q.radius {
+						ok = false
+						break
+					}
+				}
+			}
+			if !ok {
+				panic("Error: couldn't place hard disks without overlap; the board is too crowded.")
+			}
+			placed = append(placed, p)
+		}
+	} else {
+		perSide := int(math.Ceil(math.Sqrt(float64(len(b.particles)))))
+		spacing := 2 * b.MaxRadius() * 1.000001 // a little extra so rounding never makes neighbors overlap
+		side := float64(perSide-1) * spacing
+
+		// under a periodic boundary, the first and last columns and rows are also neighbors across the edges
+		needed := side
+		if b.boundary == Periodic {
+			needed += spacing
+		}
+
+		if needed > b.width || needed > b.height {
+			panic("Error: too many hard disks to pack around the center of the board.")
+		}
+
+		for i, p := range b.particles {
+			p.position.x = b.width/2 - side/2 + float64(i%perSide)*spacing
+			p.position.y = b.height/2 - side/2 + float64(i/perSide)*spacing
+		}
+	}
+}
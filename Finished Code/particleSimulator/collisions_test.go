@@ -0,0 +1,156 @@
+package main
+
+import (
+	"testing"
+)
+
+// FirstOverlap takes a Board and returns the indices of the first pair of its particles that overlap,
+// counting overlaps across periodic edges, and false if no two particles overlap.
+func FirstOverlap(b *Board) (int, int, bool) {
+	for i, p := range b.particles {
+		for j := i + 1; j < len(b.particles); j++ {
+			q := b.particles[j]
+			// allow for rounding in the positions, which are compared exactly when steps are checked
+			if b.Separation(p.position, q.position) < p.radius+q.radius-1e-9 {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// TestHardDisksNeverOverlap tests that no two disks on a crowded board overlap after any step, serially and
+// in parallel, with reflecting and periodic boundaries. The disks are placed at random, so neighbors on the board
+// are usually in different workers' chunks.
+func TestHardDisksNeverOverlap(t *testing.T) {
+	numSteps := 50
+
+	for _, boundary := range []Boundary{Reflecting, Periodic} {
+		for _, numProcs := range []int{1, 4} {
+			// 180 disks of radius 4 cover about 40% of the board
+			b := InitializeHardDiskBoard(150, 150, 180, 4, 1.0, true, 1)
+			b.boundary = boundary
+			b.PlaceHardDisks(true, 1)
+
+			if i, j, overlap := FirstOverlap(b); overlap {
+				t.Fatalf("boundary %d: disks %d and %d overlap where they were placed", boundary, i, j)
+			}
+
+			boards := UpdateBoards(b, numSteps, numProcs > 1, numProcs)
+
+			rejected := 0
+			for step := 1; step <= numSteps; step++ {
+				if i, j, overlap := FirstOverlap(boards[step]); overlap {
+					t.Fatalf("boundary %d, %d processors: disks %d and %d overlap after step %d", boundary, numProcs, i, j, step)
+				}
+				for i, p := range boards[step].particles {
+					if p.position == boards[step-1].particles[i].position {
+						rejected++
+					}
+				}
+			}
+
+			// on a board this crowded, some steps must have been rejected for the test to mean anything
+			if rejected == 0 {
+				t.Errorf("boundary %d, %d processors: no step was ever rejected", boundary, numProcs)
+			}
+		}
+	}
+}
+
+// TestHardDisksAcrossEdges tests two disks diffusing on a periodic board barely wider than both of them,
+// each in its own worker's chunk, so that they meet often both directly and across the edges.
+func TestHardDisksAcrossEdges(t *testing.T) {
+	b := InitializeHardDiskBoard(24, 24, 2, 5, 1.0, false, 1)
+	b.boundary = Periodic
+	b.particles[0].position = OrderedPair{x: 6, y: 12}
+	b.particles[1].position = OrderedPair{x: 18, y: 12}
+
+	numSteps := 1000
+	boards := UpdateBoards(b, numSteps, true, 2)
+
+	touchedAcrossEdge := false
+	for step, board := range boards {
+		p, q := board.particles[0].position, board.particles[1].position
+		separation := board.Separation(p, q)
+		if separation < 10-1e-9 {
+			t.Fatalf("disks are %g apart after step %d, want at least 10", separation, step)
+		}
+		if separation < 10.5 && Distance(p, q) > separation+1e-9 {
+			touchedAcrossEdge = true
+		}
+	}
+
+	if !touchedAcrossEdge {
+		t.Errorf("disks never came close across the edges of the board")
+	}
+}
+
+// TestImages tests that a position only has copies within reach of the edges of a periodic board.
+func TestImages(t *testing.T) {
+	tests := []struct {
+		boundary Boundary
+		pos      OrderedPair
+		want     []OrderedPair
+	}{
+		{Periodic, OrderedPair{50, 50}, []OrderedPair{{50, 50}}},
+		{Periodic, OrderedPair{2, 50}, []OrderedPair{{2, 50}, {102, 50}}},
+		{Periodic, OrderedPair{50, 95}, []OrderedPair{{50, 95}, {50, -5}}},
+		{Periodic, OrderedPair{2, 95}, []OrderedPair{{2, 95}, {2, -5}, {102, 95}, {102, -5}}},
+		{Reflecting, OrderedPair{2, 95}, []OrderedPair{{2, 95}}},
+	}
+
+	for _, test := range tests {
+		b := &Board{width: 100, height: 100, boundary: test.boundary}
+		got := b.Images(test.pos, 10)
+		if len(got) != len(test.want) {
+			t.Errorf("Images(%v) under boundary %d = %v, want %v", test.pos, test.boundary, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("Images(%v) under boundary %d = %v, want %v", test.pos, test.boundary, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+// TestPlaceHardDisks tests that random and lattice placement never overlap disks, including across the edges
+// of a periodic board, and that a lattice that only fits without its periodic neighbors is rejected.
+func TestPlaceHardDisks(t *testing.T) {
+	for _, boundary := range []Boundary{Reflecting, Periodic} {
+		for _, random := range []bool{true, false} {
+			b := InitializeBoard(100, 100, 150, 3, 1.0, random, 2)
+			b.boundary = boundary
+			b.PlaceHardDisks(random, 2)
+
+			if i, j, overlap := FirstOverlap(b); overlap {
+				t.Errorf("boundary %d, random %v: disks %d and %d overlap", boundary, random, i, j)
+			}
+			for i, p := range b.particles {
+				if p.position.x < 0 || p.position.x > b.width || p.position.y < 0 || p.position.y > b.height {
+					t.Errorf("boundary %d, random %v: disk %d placed off the board at %v", boundary, random, i, p.position)
+				}
+			}
+		}
+	}
+
+	// a 17 x 17 lattice of disks of radius 3 spans 96 of the 100 units, leaving too little room across the edges
+	for _, boundary := range []Boundary{Reflecting, Periodic} {
+		b := InitializeBoard(100, 100, 289, 3, 1.0, false, 2)
+		b.boundary = boundary
+
+		panicked := func() (panicked bool) {
+			defer func() {
+				panicked = recover() != nil
+			}()
+			b.PlaceHardDisks(false, 2)
+			return false
+		}()
+
+		if panicked != (boundary == Periodic) {
+			t.Errorf("boundary %d: PlaceHardDisks panicked = %v, want %v", boundary, panicked, boundary == Periodic)
+		}
+	}
+}
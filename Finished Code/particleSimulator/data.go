@@ -1,18 +1,56 @@
 package main
 
+import "math/rand"
+
 // Particle represents each individual particle in our diffusion simulation.
 type Particle struct {
 	position         OrderedPair
 	name             string
 	radius           float64
-	diffusionRate    float64 // length of single step
-	red, green, blue uint8   // color object
+	diffusionRate    float64     // length of single step
+	red, green, blue uint8       // color object
+	displacement     OrderedPair // the net displacement of the particle from where it started, unwrapped across periodic edges
+	stuck            bool        // true once the particle has stuck to a sticky edge of the board
+	absorbed         bool        // true if the particle has left through an absorbing edge and is about to be removed
 }
 
 // Board represents the visible part of the simulation.
 type Board struct {
 	width, height float64
 	particles     []*Particle
+	hardDisks     bool // if true, particles are hard disks and moves that would make two of them overlap are rejected
+	boundary      Boundary
+	walls         []Wall       // internal walls and membranes, which are never changed so boards can share them
+	absorbed      int          // the number of particles removed by an absorbing boundary so far
+	species       []Species    // the kinds of particles on the board, which are never changed so boards can share them
+	reactions     []Reaction   // the reactions between species, which are never changed so boards can share them
+	seed          int64        // the master seed from which all of the board's random streams are derived
+	streams       []*rand.Rand // one random stream per worker, shared by the boards of a run and drawn from in order
+	reactionRand  *rand.Rand   // the random stream of the serial reaction step
+}
+
+// Species describes one kind of particle: its name, size, diffusion rate, and color.
+type Species struct {
+	name             string
+	radius           float64
+	diffusionRate    float64
+	red, green, blue uint8
+}
+
+// Reaction is a rule A + B -> C under which a particle of species reactant1 and one of species reactant2
+// that are within radius of each other are replaced by a particle of species product with the given probability per step.
+type Reaction struct {
+	reactant1, reactant2 string
+	product              string
+	radius               float64
+	probability          float64
+}
+
+// Wall is a line segment inside the board that particles cannot cross, or for a membrane, can cross
+// with some probability.
+type Wall struct {
+	start, end   OrderedPair
+	permeability float64 // the probability that a particle trying to cross passes through; 0 for a solid wall
 }
 
 // OrderedPair is an object that represents a point or vector in two-dimensional space.
@@ -5,9 +5,15 @@ package main
 import (
 	"math"
 	"math/rand"
-	"runtime"
+	"rng"
 )
 
+// the random stream used to place particles, kept apart from the worker streams 0, 1, 2, ...
+const placementStream = -1
+
+// the random stream used by reactions
+const reactionStream = -2
+
 // CopyBoard is a Board method that makes a deep copy of a board and returns
 // a pointer to it.
 func (b *Board) CopyBoard() *Board {
@@ -15,6 +21,15 @@ func (b *Board) CopyBoard() *Board {
 
 	newBoard.width = b.width
 	newBoard.height = b.height
+	newBoard.hardDisks = b.hardDisks
+	newBoard.boundary = b.boundary
+	newBoard.walls = b.walls
+	newBoard.absorbed = b.absorbed
+	newBoard.species = b.species
+	newBoard.reactions = b.reactions
+	newBoard.seed = b.seed
+	newBoard.streams = b.streams
+	newBoard.reactionRand = b.reactionRand
 	newBoard.particles = make([]*Particle, len(b.particles))
 
 	for i, p := range b.particles {
@@ -36,16 +51,17 @@ func (p *Particle) CopyParticle() *Particle {
 	return &p2
 }
 
-// UpdateBoards takes a pointer to an initial Board object, a number of steps parameter, and a boolean flag isParallel.
+// UpdateBoards takes a pointer to an initial Board object, a number of steps parameter, a boolean flag isParallel,
+// and a number of processors.
 // It returns a slice of pointers to Board objects corresponding to simulating diffusion
 // over the number of steps given.
-// It runs the algorithm serially if isParallel is false and in parallel if isParallel is true.
-func UpdateBoards(initialBoard *Board, numSteps int, isParallel bool) []*Board {
+// It runs the algorithm serially if isParallel is false and in parallel over numProcs workers if isParallel is true.
+func UpdateBoards(initialBoard *Board, numSteps int, isParallel bool, numProcs int) []*Board {
 	boards := make([]*Board, numSteps+1)
 	boards[0] = initialBoard
 
 	for i := 1; i <= numSteps; i++ {
-		boards[i] = boards[i-1].UpdateBoard(isParallel)
+		boards[i] = boards[i-1].UpdateBoard(isParallel, numProcs)
 	}
 
 	return boards
@@ -53,56 +69,115 @@ func UpdateBoards(initialBoard *Board, numSteps int, isParallel bool) []*Board {
 
 // UpdateBoard is a Board method that returns a pointer to a new Board object
 // corresponding to a single time step update of the Board.
-// It takes a boolean input isParallel.
-// It runs the algorithm serially if isParallel is false and in parallel if isParallel is true.
-func (b *Board) UpdateBoard(isParallel bool) *Board {
+// It takes a boolean input isParallel and a number of processors.
+// It runs the algorithm serially if isParallel is false and in parallel over numProcs workers if isParallel is true.
+func (b *Board) UpdateBoard(isParallel bool, numProcs int) *Board {
 	newBoard := b.CopyBoard()
 
-	newBoard.Diffuse(isParallel)
+	newBoard.Diffuse(isParallel, numProcs)
+	newBoard.React()
 
 	return newBoard
 }
 
 // Diffuse is a Board method that diffuses each Particle in the Board over a single time step.
-// It takes a boolean input isParallel.
-// It runs the algorithm serially if isParallel is false and in parallel if isParallel is true.
-func (b *Board) Diffuse(isParallel bool) {
+// It takes a boolean input isParallel and a number of processors.
+// It runs the algorithm serially if isParallel is false and in parallel over numProcs workers if isParallel is true.
+// Each worker draws from its own stream, so a run is only repeated exactly with the same seed and numProcs.
+func (b *Board) Diffuse(isParallel bool, numProcs int) {
 	// if isParallel is true, I want the diffusion process to happen in parallel, meaning that I'll need to adjust the existing code.
 	if isParallel {
 		// I have to do parallel work now
-		numProcs := runtime.NumCPU()
 		b.DiffuseParallel(numProcs)
+	} else if b.hardDisks { // serial case with collisions
+		b.DiffuseHardDisks(1)
 	} else { // serial case
+		r := b.Streams(1)[0]
 		for _, p := range b.particles {
-			p.RandStep()
+			b.Step(p, r)
 		}
+		b.RemoveAbsorbed()
+	}
+}
+
+// Streams is a Board method that takes a number of workers n and returns n random streams, one for each worker.
+// Stream i is seeded with rng.DeriveSeed(b.seed, i) the first time it is asked for and is then shared by every
+// later Board of the run, so the same seed and number of workers always give the same simulation.
+// It must not be called by more than one goroutine at a time.
+func (b *Board) Streams(n int) []*rand.Rand {
+	for len(b.streams) < n {
+		b.streams = append(b.streams, rng.New(rng.DeriveSeed(b.seed, len(b.streams))))
 	}
+	return b.streams[:n]
 }
 
-// RandStep is a Particle method that moves the Particle by the Particle's diffusion rate
-// parameter in a randomly chosen direction.
-func (p *Particle) RandStep() {
+// ReactionStream is a Board method that returns the random stream of its reactions, creating it on first use.
+func (b *Board) ReactionStream() *rand.Rand {
+	if b.reactionRand == nil {
+		b.reactionRand = rng.New(rng.DeriveSeed(b.seed, reactionStream))
+	}
+	return b.reactionRand
+}
+
+// Step is a Board method that takes a pointer to one of its particles and a random stream, and moves the particle
+// one random step, respecting the walls and the boundary of the Board.
+func (b *Board) Step(p *Particle, r *rand.Rand) {
+	if p.stuck {
+		return
+	}
+
+	pos, fate := b.ResolveStep(p.position, p.ProposeStep(r), r)
+	b.MoveParticle(p, pos)
+	p.ApplyFate(fate)
+}
+
+// MoveParticle is a Board method that takes a pointer to one of its particles and the position it ends a step at.
+// It moves the particle there and adds the step to the particle's displacement. Under a periodic boundary,
+// a step that wraps around an edge counts as the short step it really was rather than a jump across the board.
+func (b *Board) MoveParticle(p *Particle, pos OrderedPair) {
+	dx := pos.x - p.position.x
+	dy := pos.y - p.position.y
+
+	if b.boundary == Periodic {
+		dx -= b.width * math.Round(dx/b.width)
+		dy -= b.height * math.Round(dy/b.height)
+	}
+
+	p.displacement.x += dx
+	p.displacement.y += dy
+	p.position = pos
+}
+
+// ProposeStep is a Particle method that takes a random stream and returns the position the Particle would reach
+// by moving its diffusion rate in a randomly chosen direction, without moving it.
+func (p *Particle) ProposeStep(r *rand.Rand) OrderedPair {
 	stepLength := p.diffusionRate
-	angle := rand.Float64() * 2 * math.Pi
-	p.position.x += stepLength * math.Cos(angle)
-	p.position.y += stepLength * math.Sin(angle)
+	angle := r.Float64() * 2 * math.Pi
+	return OrderedPair{
+		x: p.position.x + stepLength*math.Cos(angle),
+		y: p.position.y + stepLength*math.Sin(angle),
+	}
 }
 
 // InitializeBoard takes board parameters and initializes a Board with these parameters
 // for a collection of randomly placed particles in the Board.
-func InitializeBoard(boardWidth, boardHeight float64, numParticles int, particleRadius float64, diffusionRate float64, random bool) *Board {
+// The seed is the master seed of every random number drawn in the simulation, from placing the particles onward.
+func InitializeBoard(boardWidth, boardHeight float64, numParticles int, particleRadius float64, diffusionRate float64, random bool, seed int64) *Board {
 	var b Board
 
 	b.width = boardWidth
 	b.height = boardHeight
+	b.seed = seed
+
+	placement := rng.New(rng.DeriveSeed(seed, placementStream))
 
 	b.particles = make([]*Particle, numParticles)
 
 	for i := range b.particles {
 		var p Particle
 		if random {
-			p.position.x = rand.Float64() * boardWidth
-			p.position.y = rand.Float64() * boardHeight
+			p.position.x = placement.Float64() * boardWidth
+			p.position.y = placement.Float64() * boardHeight
 		} else {
 			// default: non-random: assign all to center of board
 			p.position.x = boardWidth / 2
@@ -3,11 +3,35 @@ package main
 import (
 	"fmt"
 	"gifhelper"
+	"os"
+	"rng"
+	"runtime"
+	"strconv"
 )
 
 func main() {
 	fmt.Println("Particle simulator.")
 
+	// an optional seed on the command line repeats an earlier run; otherwise the seed comes from the clock
+	seedString := "time"
+	if len(os.Args) > 1 {
+		seedString = os.Args[1]
+	}
+	seed, err := rng.ParseSeed(seedString)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Seed:", seed)
+
+	// the number of workers of a parallel run comes next, since a run is only repeated with the same seed and workers
+	numProcs := runtime.NumCPU()
+	if len(os.Args) > 2 {
+		numProcs, err = strconv.Atoi(os.Args[2])
+		if err != nil || numProcs < 1 {
+			panic("Error: number of processors must be a positive integer.")
+		}
+	}
+
 	fmt.Println("Generating random particles and initializing board.")
 
 	numParticles := 100
@@ -16,21 +40,84 @@ func main() {
 	particleRadius := 5.0
 	diffusionRate := 1.0
 
-	//assumption: all particles are white
+	//assumption: all particles are white, unless a reaction file gives several species
 
 	random := false // make true if we want to scatter across board
 
-	initialBoard := InitializeBoard(boardWidth, boardHeight, numParticles, particleRadius, diffusionRate, random)
+	hardDisks := false // make true if particles should collide rather than overlap
+
+	boundary := Reflecting // what happens at the edges: Unbounded, Reflecting, Absorbing, Periodic, or Sticky
+
+	wallFile := "" // set to a file such as "walls/channel.txt" to add walls and membranes
+
+	reactionFile := "" // set to a file such as "reactions/abc.txt" to simulate several species that react
+
+	var initialBoard *Board
+	var species []Species
+	if reactionFile != "" {
+		var counts []int
+		var reactions []Reaction
+		species, counts, reactions, err = ReadReactionFile(reactionFile)
+		if err != nil {
+			panic(err)
+		}
+		initialBoard = InitializeSpeciesBoard(boardWidth, boardHeight, species, counts, reactions, random, hardDisks, seed)
+	} else if hardDisks {
+		initialBoard = InitializeHardDiskBoard(boardWidth, boardHeight, numParticles, particleRadius, diffusionRate, random, seed)
+	} else {
+		initialBoard = InitializeBoard(boardWidth, boardHeight, numParticles, particleRadius, diffusionRate, random, seed)
+	}
+
+	initialBoard.boundary = boundary
 
-	fmt.Println("Running simulation in serial.")
+	// disks on a periodic board may also overlap across its edges, which they can only be kept from once the boundary is set
+	if initialBoard.hardDisks && boundary == Periodic {
+		initialBoard.PlaceHardDisks(random, seed)
+	}
+
+	if wallFile != "" {
+		walls, err := ReadWallsFromFile(wallFile)
+		if err != nil {
+			panic(err)
+		}
+		initialBoard.walls = walls
+	}
 
 	numSteps := 2000
 
 	isParallel := false
 
-	boards := UpdateBoards(initialBoard, numSteps, isParallel)
+	if isParallel {
+		fmt.Println("Running simulation in parallel over", numProcs, "workers.")
+	} else {
+		fmt.Println("Running simulation in serial.")
+	}
+
+	boards := UpdateBoards(initialBoard, numSteps, isParallel, numProcs)
+
+	if boundary == Absorbing {
+		finalBoard := boards[len(boards)-1]
+		fmt.Printf("%d particles absorbed, %d remaining.\n", finalBoard.absorbed, len(finalBoard.particles))
+	}
+
+	if reactionFile != "" {
+		WriteSpeciesCountsToFile(SpeciesCounts(boards, species), species, "species.csv")
+		fmt.Println("Species counts written to species.csv.")
+	}
+
+	if reactionFile != "" {
+		// species step at different rates and products appear partway through, so no single Gaussian describes them
+		fmt.Println("Simulation run. Skipping the diffusion analysis, which assumes a single species.")
+	} else {
+		fmt.Println("Simulation run. Analyzing diffusion.")
+
+		// sample the histograms every 200 steps, in 20 bins of width 5
+		fitted, theoretical := AnalyzeDiffusion(boards, diffusionRate, 200, 20, 5.0)
+		fmt.Printf("Fitted diffusion coefficient %.4f, theoretical %.4f.\n", fitted, theoretical)
+		fmt.Println("Mean squared displacement written to msd.csv, histograms to radial.csv and profile.csv.")
+	}
 
-	fmt.Println("Simulation run. Animating system.")
+	fmt.Println("Animating system.")
 	canvasWidth := 300
 	frequency := 10
 	images := AnimateSystem(boards, canvasWidth, frequency)
@@ -2,45 +2,37 @@ package main
 
 //this is where we will put functions that correspond only to the parallel simulation.
 
+import (
+	"math/rand"
+	"workpool"
+)
+
 // DiffuseParallel is a Board method that takes as input an integer numProcs.
 // It updates the board by diffusing each particle one time step, dividing the work over numProcs workers.
+// Hard disks need to know where every other particle is going, so they are handled by DiffuseHardDisks.
 func (b *Board) DiffuseParallel(numProcs int) {
-	numParticles := len(b.particles)
-
-	finished := make(chan bool, numProcs)
-
-	// split the work over numProcs processors
-	for i := 0; i < numProcs; i++ {
-		// each processor needs about the same number of particles
-
-		//figure out which start and end indices of a this worker is getting assigned
-		chunkSize := numParticles / numProcs
-		startIndex := i * chunkSize
-		endIndex := startIndex + chunkSize
-
-		if i == numProcs-1 {
-			// if we're in the final subslice, we need to make sure to extend it all the way to the end of a
-			endIndex = numParticles
-		}
-
-		go DiffuseOneProc(b.particles[startIndex:endIndex], finished)
+	if b.hardDisks {
+		b.DiffuseHardDisks(numProcs)
+		return
 	}
-	// we can't end the function until all goroutines are finished
 
-	// now we need a separate for loop to grab from channel
-	for i := 0; i < numProcs; i++ {
-		<-finished
-	}
+	// every worker draws from its own random stream, so that runs can be repeated whatever order the workers run in
+	streams := b.Streams(numProcs)
 
-	// now the function can end because receiving will block until I've received numProcs times
+	// split the particles over numProcs processors, each getting about the same number, and wait for all of them
+	workpool.For(len(b.particles), numProcs, func(worker, start, end int) {
+		b.DiffuseOneProc(b.particles[start:end], streams[worker])
+	})
+
+	// particles that left through an absorbing boundary are removed once every worker is done with the slice
+	b.RemoveAbsorbed()
 }
 
-func DiffuseOneProc(particles []*Particle, finished chan bool) {
+// DiffuseOneProc is a Board method that takes a slice of its particles and a random stream,
+// and moves each particle one random step using numbers from r.
+func (b *Board) DiffuseOneProc(particles []*Particle, r *rand.Rand) {
 	// all we have to do is range over the particles and take a random step with each one
 	for _, p := range particles {
-		p.RandStep()
+		b.Step(p, r)
 	}
-
-	// hey, I'm done
-	finished <- true
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// MSDStdErr takes a number of steps of a given length and a number of walkers, and returns the standard error of
+// their mean squared displacement. The squared displacement of a walk of n steps of length l in uniformly random
+// directions has variance n(n-1)l^4, since the cosines of the angles between different pairs of steps are uncorrelated.
+func MSDStdErr(numSteps int, stepLength float64, numWalkers int) float64 {
+	n := float64(numSteps)
+	return stepLength * stepLength * math.Sqrt(n*(n-1)/float64(numWalkers))
+}
+
+// TestSerialParallelEquivalent tests that serial and parallel runs with different seeds give mean squared
+// displacements that agree with each other, and with the numSteps * stepLength^2 of a random walk, within
+// statistical error, both for point particles and for hard disks.
+func TestSerialParallelEquivalent(t *testing.T) {
+	numParticles := 400
+	numSteps := 100
+	diffusionRate := 1.0
+	want := float64(numSteps) * diffusionRate * diffusionRate
+
+	for _, hardDisks := range []bool{false, true} {
+		var serialBoard, parallelBoard *Board
+		if hardDisks {
+			// spread the disks out so that they rarely meet, which would slow their diffusion
+			serialBoard = InitializeHardDiskBoard(2000, 2000, numParticles, 1, diffusionRate, true, 1)
+			parallelBoard = InitializeHardDiskBoard(2000, 2000, numParticles, 1, diffusionRate, true, 2)
+		} else {
+			serialBoard = InitializeBoard(1000, 1000, numParticles, 5, diffusionRate, false, 1)
+			parallelBoard = InitializeBoard(1000, 1000, numParticles, 5, diffusionRate, false, 2)
+		}
+		serialBoard.boundary = Unbounded
+		parallelBoard.boundary = Unbounded
+
+		serial := UpdateBoards(serialBoard, numSteps, false, 1)
+		parallel := UpdateBoards(parallelBoard, numSteps, true, 4)
+
+		serialMean := MeanSquaredDisplacement(serial)[numSteps]
+		parallelMean := MeanSquaredDisplacement(parallel)[numSteps]
+		serialErr := MSDStdErr(numSteps, diffusionRate, len(serial[numSteps].particles))
+		parallelErr := MSDStdErr(numSteps, diffusionRate, len(parallel[numSteps].particles))
+
+		if math.Abs(serialMean-parallelMean) > 4*math.Hypot(serialErr, parallelErr) {
+			t.Errorf("hard disks %v: serial MSD %g +/- %g and parallel MSD %g +/- %g disagree",
+				hardDisks, serialMean, serialErr, parallelMean, parallelErr)
+		}
+		if math.Abs(serialMean-want) > 4*serialErr || math.Abs(parallelMean-want) > 4*parallelErr {
+			t.Errorf("hard disks %v: MSD %g +/- %g (serial) and %g +/- %g (parallel), want %g",
+				hardDisks, serialMean, serialErr, parallelMean, parallelErr, want)
+		}
+	}
+}
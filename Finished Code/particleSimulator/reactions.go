@@ -0,0 +1,326 @@
+package main
+
+//this file contains particles of several species that react with each other as they diffuse.
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"rng"
+	"strconv"
+	"strings"
+)
+
+// ReadReactionFile takes the name of a file describing a reaction-diffusion system and returns its species,
+// the initial number of particles of each species, and its reactions.
+// Blank lines and lines starting with # are ignored. Every other line is either a species or a reaction:
+//    species NAME COUNT RADIUS DIFFUSIONRATE RED GREEN BLUE
+//    reaction REACTANT1 REACTANT2 PRODUCT RADIUS PROBABILITY
+// Every species named in a reaction must be declared by a species line.
+func ReadReactionFile(filename string) ([]Species, []int, []Reaction, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("couldn't open reaction file %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	species := make([]Species, 0)
+	counts := make([]int, 0)
+	reactions := make([]Reaction, 0)
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+
+		switch fields[0] {
+		case "species":
+			if len(fields) != 8 {
+				return nil, nil, nil, fmt.Errorf("species on line %d of %s needs 7 values, got %q", lineNumber, filename, line)
+			}
+
+			var s Species
+			s.name = fields[1]
+			count, err1 := strconv.Atoi(fields[2])
+			radius, err2 := strconv.ParseFloat(fields[3], 64)
+			rate, err3 := strconv.ParseFloat(fields[4], 64)
+			red, err4 := strconv.ParseUint(fields[5], 10, 8)
+			green, err5 := strconv.ParseUint(fields[6], 10, 8)
+			blue, err6 := strconv.ParseUint(fields[7], 10, 8)
+
+			if err1 != nil || err2 != nil || err3 != nil || err4 != nil || err5 != nil || err6 != nil || count < 0 || radius < 0 || rate < 0 {
+				return nil, nil, nil, fmt.Errorf("bad species values on line %d of %s", lineNumber, filename)
+			}
+			if SpeciesIndex(species, s.name) != -1 {
+				return nil, nil, nil, fmt.Errorf("species %s is declared twice in %s", s.name, filename)
+			}
+
+			s.radius, s.diffusionRate = radius, rate
+			s.red, s.green, s.blue = uint8(red), uint8(green), uint8(blue)
+			species = append(species, s)
+			counts = append(counts, count)
+
+		case "reaction":
+			if len(fields) != 6 {
+				return nil, nil, nil, fmt.Errorf("reaction on line %d of %s needs 5 values, got %q", lineNumber, filename, line)
+			}
+
+			var r Reaction
+			r.reactant1, r.reactant2, r.product = fields[1], fields[2], fields[3]
+			radius, err1 := strconv.ParseFloat(fields[4], 64)
+			probability, err2 := strconv.ParseFloat(fields[5], 64)
+
+			if err1 != nil || err2 != nil || radius < 0 || probability < 0 || probability > 1 {
+				return nil, nil, nil, fmt.Errorf("bad reaction values on line %d of %s", lineNumber, filename)
+			}
+
+			r.radius, r.probability = radius, probability
+			reactions = append(reactions, r)
+
+		default:
+			return nil, nil, nil, fmt.Errorf("line %d of %s should start with species or reaction, got %q", lineNumber, filename, fields[0])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, nil, fmt.Errorf("couldn't read reaction file %s: %v", filename, err)
+	}
+
+	for _, r := range reactions {
+		for _, name := range []string{r.reactant1, r.reactant2, r.product} {
+			if SpeciesIndex(species, name) == -1 {
+				return nil, nil, nil, fmt.Errorf("reaction in %s uses undeclared species %s", filename, name)
+			}
+		}
+	}
+
+	return species, counts, reactions, nil
+}
+
+// SpeciesIndex takes a slice of Species and a name, and returns the index of the species with that name, or -1.
+func SpeciesIndex(species []Species, name string) int {
+	for i := range species {
+		if species[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// MakeParticle is a Species method that takes a position and returns a pointer to a new Particle of the species there.
+func (s Species) MakeParticle(position OrderedPair) *Particle {
+	var p Particle
+	p.position = position
+	p.name = s.name
+	p.radius = s.radius
+	p.diffusionRate = s.diffusionRate
+	p.red, p.green, p.blue = s.red, s.green, s.blue
+	return &p
+}
+
+// InitializeSpeciesBoard takes the dimensions of a board, a slice of Species with the number of particles of each,
+// a slice of Reactions, booleans random and hardDisks, and a seed. It returns a Board holding counts[i] particles of
+// species i, placed at random if random is true and at the center of the board otherwise, as in InitializeBoard.
+// If hardDisks is true, the particles are instead placed without overlap by PlaceHardDisks, as in InitializeHardDiskBoard.
+func InitializeSpeciesBoard(boardWidth, boardHeight float64, species []Species, counts []int, reactions []Reaction, random, hardDisks bool, seed int64) *Board {
+	var b Board
+
+	b.width = boardWidth
+	b.height = boardHeight
+	b.seed = seed
+	b.species = species
+	b.reactions = reactions
+	b.particles = make([]*Particle, 0)
+
+	placement := rng.New(rng.DeriveSeed(seed, placementStream))
+
+	for i, s := range species {
+		for k := 0; k < counts[i]; k++ {
+			position := OrderedPair{x: boardWidth / 2, y: boardHeight / 2}
+			if random {
+				position = OrderedPair{x: placement.Float64() * boardWidth, y: placement.Float64() * boardHeight}
+			}
+			b.particles = append(b.particles, s.MakeParticle(position))
+		}
+	}
+
+	if hardDisks {
+		b.hardDisks = true
+		b.PlaceHardDisks(random, seed)
+	}
+
+	return &b
+}
+
+// FindReaction is a Board method that takes the names of two species and returns the first reaction between them,
+// in either order, and false if they don't react.
+func (b *Board) FindReaction(name1, name2 string) (Reaction, bool) {
+	for _, r := range b.reactions {
+		if (r.reactant1 == name1 && r.reactant2 == name2) || (r.reactant1 == name2 && r.reactant2 == name1) {
+			return r, true
+		}
+	}
+	return Reaction{}, false
+}
+
+// React is a Board method that carries out one step of the Board's reactions. Each pair of particles within
+// the radius of a reaction between their species reacts with that reaction's probability, and a particle takes
+// part in at most one reaction per step. The two reactants are replaced by a particle of the product at their
+// midpoint, whose displacement is the mean of theirs. On a Board of hard disks, a reaction whose product would overlap
+// any particle other than its reactants, or a product made earlier in the step, doesn't happen. Pairs are considered
+// in the order of the particles, so this step is serial even when diffusion is parallel, and draws from the Board's
+// reaction stream.
+func (b *Board) React() {
+	if len(b.reactions) == 0 || len(b.particles) < 2 {
+		return
+	}
+
+	maxRadius := 0.0
+	for _, r := range b.reactions {
+		maxRadius = math.Max(maxRadius, r.radius)
+	}
+
+	positions := make([]OrderedPair, len(b.particles))
+	for i, p := range b.particles {
+		positions[i] = p.position
+	}
+	grid := MakeCellGrid(positions, math.Max(maxRadius, 1e-9))
+	random := b.ReactionStream()
+
+	// a product can only overlap particles within its radius plus the largest radius on the board
+	var diskGrid CellGrid
+	reach := 0.0
+	if b.hardDisks {
+		for _, r := range b.reactions {
+			reach = math.Max(reach, b.species[SpeciesIndex(b.species, r.product)].radius)
+		}
+		reach += b.MaxRadius()
+		diskGrid = MakeCellGrid(positions, math.Max(reach, 1e-9))
+	}
+
+	consumed := make([]bool, len(b.particles))
+	products := make([]*Particle, 0)
+
+	for i, p := range b.particles {
+		if consumed[i] {
+			continue
+		}
+
+		for _, j := range grid.Near(p.position) {
+			// consider each pair once, from its first particle
+			if j <= i || consumed[j] {
+				continue
+			}
+
+			q := b.particles[j]
+			r, ok := b.FindReaction(p.name, q.name)
+			if !ok || Distance(p.position, q.position) > r.radius || random.Float64() >= r.probability {
+				continue
+			}
+
+			midpoint := OrderedPair{x: (p.position.x + q.position.x) / 2, y: (p.position.y + q.position.y) / 2}
+			product := b.species[SpeciesIndex(b.species, r.product)].MakeParticle(midpoint)
+			if b.hardDisks && b.ProductOverlaps(product, i, j, consumed, products, diskGrid, reach) {
+				continue
+			}
+			product.displacement = OrderedPair{x: (p.displacement.x + q.displacement.x) / 2, y: (p.displacement.y + q.displacement.y) / 2}
+
+			consumed[i], consumed[j] = true, true
+			products = append(products, product)
+			break
+		}
+	}
+
+	if len(products) == 0 {
+		return
+	}
+
+	kept := make([]*Particle, 0, len(b.particles))
+	for i, p := range b.particles {
+		if !consumed[i] {
+			kept = append(kept, p)
+		}
+	}
+	b.particles = append(kept, products...)
+}
+
+// ProductOverlaps is a Board method that takes a new product of a reaction, the indices i and j of its reactants, which
+// particles have already been consumed, the products made so far in the step, and a CellGrid of the positions of
+// the Board's particles whose cell size is at least reach. It returns true if the product would overlap a particle
+// that is neither a reactant nor consumed, or one of the products, counting overlaps across periodic edges.
+func (b *Board) ProductOverlaps(product *Particle, i, j int, consumed []bool, products []*Particle, grid CellGrid, reach float64) bool {
+	for _, image := range b.Images(product.position, reach) {
+		for _, k := range grid.Near(image) {
+			if k == i || k == j || consumed[k] {
+				continue
+			}
+			if Distance(image, grid.points[k]) < product.radius+b.particles[k].radius {
+				return true
+			}
+		}
+
+		for _, other := range products {
+			if Distance(image, other.position) < product.radius+other.radius {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// SpeciesCounts takes a slice of Boards and a slice of Species, and returns the number of particles of each species
+// on every Board, so that counts[t][i] is the number of particles of species i on Board t.
+func SpeciesCounts(boards []*Board, species []Species) [][]int {
+	counts := make([][]int, len(boards))
+
+	for t, b := range boards {
+		counts[t] = make([]int, len(species))
+		for _, p := range b.particles {
+			if i := SpeciesIndex(species, p.name); i != -1 {
+				counts[t][i]++
+			}
+		}
+	}
+
+	return counts
+}
+
+// WriteSpeciesCountsToFile takes the counts of every species at every step, the Species, and a file name,
+// and writes one line per step to a CSV file.
+func WriteSpeciesCountsToFile(counts [][]int, species []Species, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprint(writer, "step")
+	for _, s := range species {
+		fmt.Fprint(writer, ",", s.name)
+	}
+	fmt.Fprintln(writer)
+
+	for t := range counts {
+		fmt.Fprint(writer, t)
+		for _, c := range counts[t] {
+			fmt.Fprint(writer, ",", c)
+		}
+		fmt.Fprintln(writer)
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// ReactionBoard takes a slice of Species, the reactions between them, and the names and positions of particles,
+// and returns a 100 x 100 reflecting Board holding those particles.
+func ReactionBoard(species []Species, reactions []Reaction, names []string, positions []OrderedPair) *Board {
+	b := &Board{width: 100, height: 100, boundary: Reflecting, species: species, reactions: reactions, seed: 1}
+	for i, name := range names {
+		b.particles = append(b.particles, species[SpeciesIndex(species, name)].MakeParticle(positions[i]))
+	}
+	return b
+}
+
+// TestReadReactionFile tests that ReadReactionFile reads species and reactions, skipping comments and blank lines,
+// and returns an error for every kind of malformed reaction file.
+func TestReadReactionFile(t *testing.T) {
+	dir := t.TempDir()
+
+	good := "# two species\n\nspecies A 10 2 1.5 255 0 0\n  species B 0 3 0.5 0 128 255  \nreaction A A B 4 0.25\n"
+	species, counts, reactions, err := ReadReactionFile(WriteTempFile(t, dir, "good.txt", good))
+	if err != nil {
+		t.Fatalf("couldn't read a good reaction file: %v", err)
+	}
+	wantSpecies := []Species{
+		{name: "A", radius: 2, diffusionRate: 1.5, red: 255},
+		{name: "B", radius: 3, diffusionRate: 0.5, green: 128, blue: 255},
+	}
+	if len(species) != len(wantSpecies) || len(counts) != len(wantSpecies) {
+		t.Fatalf("read %d species with %d counts, want %d", len(species), len(counts), len(wantSpecies))
+	}
+	for i := range wantSpecies {
+		if species[i] != wantSpecies[i] {
+			t.Errorf("species %d = %v, want %v", i, species[i], wantSpecies[i])
+		}
+	}
+	if counts[0] != 10 || counts[1] != 0 {
+		t.Errorf("counts = %v, want [10 0]", counts)
+	}
+	wantReaction := Reaction{reactant1: "A", reactant2: "A", product: "B", radius: 4, probability: 0.25}
+	if len(reactions) != 1 || reactions[0] != wantReaction {
+		t.Errorf("reactions = %v, want [%v]", reactions, wantReaction)
+	}
+
+	bad := map[string]string{
+		"unknown keyword":          "particle A 10 2 1.5 255 0 0\n",
+		"species too short":        "species A 10 2 1.5 255 0\n",
+		"species too long":         "species A 10 2 1.5 255 0 0 0\n",
+		"count not a number":       "species A x 2 1.5 255 0 0\n",
+		"negative count":           "species A -1 2 1.5 255 0 0\n",
+		"negative radius":          "species A 10 -2 1.5 255 0 0\n",
+		"negative rate":            "species A 10 2 -1.5 255 0 0\n",
+		"color out of range":       "species A 10 2 1.5 256 0 0\n",
+		"duplicate species":        "species A 10 2 1.5 255 0 0\nspecies A 5 2 1.5 255 0 0\n",
+		"reaction too short":       "species A 10 2 1.5 255 0 0\nreaction A A A 4\n",
+		"negative reaction radius": "species A 10 2 1.5 255 0 0\nreaction A A A -4 0.5\n",
+		"probability above 1":      "species A 10 2 1.5 255 0 0\nreaction A A A 4 1.5\n",
+		"negative probability":     "species A 10 2 1.5 255 0 0\nreaction A A A 4 -0.5\n",
+		"undeclared reactant":      "species A 10 2 1.5 255 0 0\nreaction A B A 4 0.5\n",
+		"undeclared product":       "species A 10 2 1.5 255 0 0\nreaction A A C 4 0.5\n",
+	}
+	for name, contents := range bad {
+		if _, _, _, err := ReadReactionFile(WriteTempFile(t, dir, "bad.txt", contents)); err == nil {
+			t.Errorf("%s: ReadReactionFile returned no error for %q", name, contents)
+		}
+	}
+
+	if _, _, _, err := ReadReactionFile(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Errorf("ReadReactionFile returned no error for a missing file")
+	}
+
+	// the example that comes with the simulator
+	species, counts, reactions, err = ReadReactionFile(filepath.Join("reactions", "abc.txt"))
+	if err != nil {
+		t.Fatalf("couldn't read reactions/abc.txt: %v", err)
+	}
+	if len(species) != 3 || counts[0] != 500 || counts[1] != 500 || counts[2] != 0 || len(reactions) != 1 {
+		t.Errorf("reactions/abc.txt gave species %v with counts %v and reactions %v", species, counts, reactions)
+	}
+}
+
+// TestReactWithinRadius tests a reaction A + B -> C of probability 1 on pairs placed closer than, exactly at, and
+// farther than its radius, along with a close pair of particles that don't react with each other.
+func TestReactWithinRadius(t *testing.T) {
+	species := []Species{{name: "A", radius: 0.5}, {name: "B", radius: 0.5}, {name: "C", radius: 0.5}}
+	reactions := []Reaction{{reactant1: "A", reactant2: "B", product: "C", radius: 2, probability: 1}}
+	names := []string{"A", "B", "B", "A", "A", "B", "A", "A"}
+	positions := []OrderedPair{
+		{10, 10}, {11.5, 10}, // 1.5 apart, so they react
+		{32.5, 10}, {30, 10}, // 2.5 apart, so they don't
+		{50, 10}, {52, 10}, // exactly 2 apart, so they react
+		{70, 10}, {71, 10}, // A doesn't react with A
+	}
+
+	b := ReactionBoard(species, reactions, names, positions)
+	before := b.CopyBoard()
+	b.React()
+
+	counts := SpeciesCounts([]*Board{before, b}, species)
+	if counts[0][0] != 5 || counts[0][1] != 3 || counts[0][2] != 0 {
+		t.Fatalf("counts before reacting = %v, want [5 3 0]", counts[0])
+	}
+	if counts[1][0] != 3 || counts[1][1] != 1 || counts[1][2] != 2 {
+		t.Errorf("counts after reacting = %v, want [3 1 2]", counts[1])
+	}
+
+	// the untouched particles keep their order, followed by the products at the midpoints of their reactants
+	want := []OrderedPair{{32.5, 10}, {30, 10}, {70, 10}, {71, 10}, {10.75, 10}, {51, 10}}
+	if len(b.particles) != len(want) {
+		t.Fatalf("%d particles after reacting, want %d", len(b.particles), len(want))
+	}
+	for i := range want {
+		if b.particles[i].position != want[i] {
+			t.Errorf("particle %d is at %v, want %v", i, b.particles[i].position, want[i])
+		}
+	}
+}
+
+// TestReactConservesCounts tests a crowded board of A and B reacting with probability 1. Afterward, no A and B
+// within the reaction radius are left, and every C made has used up exactly one A and one B.
+func TestReactConservesCounts(t *testing.T) {
+	species := []Species{{name: "A", radius: 0.5}, {name: "B", radius: 0.5}, {name: "C", radius: 0.5}}
+	reactions := []Reaction{{reactant1: "A", reactant2: "B", product: "C", radius: 3, probability: 1}}
+
+	before := InitializeSpeciesBoard(100, 100, species, []int{400, 300, 0}, reactions, true, false, 1)
+	b := before.CopyBoard()
+	b.React()
+
+	for i, p := range b.particles {
+		for _, q := range b.particles[i+1:] {
+			if p.name != q.name && p.name != "C" && q.name != "C" && Distance(p.position, q.position) <= 3 {
+				t.Fatalf("%s at %v and %s at %v are within the reaction radius but didn't react", p.name, p.position, q.name, q.position)
+			}
+		}
+	}
+
+	counts := SpeciesCounts([]*Board{before, b}, species)
+	if counts[1][2] == 0 {
+		t.Fatalf("no C was made")
+	}
+	if counts[1][0]+counts[1][2] != 400 || counts[1][1]+counts[1][2] != 300 {
+		t.Errorf("counts went from %v to %v, want each C to replace one A and one B", counts[0], counts[1])
+	}
+}
+
+// TestProductOverlaps tests that on a board of hard disks a product isn't made where it would overlap another
+// particle, directly or across the edges of a periodic board, and that it is made when there is room.
+func TestProductOverlaps(t *testing.T) {
+	species := []Species{{name: "A", radius: 1}, {name: "B", radius: 1}, {name: "C", radius: 3}}
+	reactions := []Reaction{{reactant1: "A", reactant2: "B", product: "C", radius: 3, probability: 1}}
+
+	tests := []struct {
+		name      string
+		boundary  Boundary
+		names     []string
+		positions []OrderedPair
+		wantC     int
+	}{
+		{"room for the product", Reflecting, []string{"A", "B"},
+			[]OrderedPair{{10, 10}, {12.5, 10}}, 1},
+		// the product at (11.25, 10) would be 3.5 from the blocking A, less than 3 + 1
+		{"blocked by a neighbor", Reflecting, []string{"A", "B", "A"},
+			[]OrderedPair{{10, 10}, {12.5, 10}, {11.25, 13.5}}, 0},
+		// the product at (2.25, 50) would be 3.75 from the blocking A across the left edge
+		{"blocked across the edge", Periodic, []string{"A", "B", "A"},
+			[]OrderedPair{{1, 50}, {3.5, 50}, {98.5, 50}}, 0},
+		{"not blocked across the edge of a reflecting board", Reflecting, []string{"A", "B", "A"},
+			[]OrderedPair{{1, 50}, {3.5, 50}, {98.5, 50}}, 1},
+	}
+
+	for _, test := range tests {
+		b := ReactionBoard(species, reactions, test.names, test.positions)
+		b.boundary = test.boundary
+		b.hardDisks = true
+		b.React()
+
+		counts := SpeciesCounts([]*Board{b}, species)
+		if counts[0][2] != test.wantC {
+			t.Errorf("%s: made %d C, want %d", test.name, counts[0][2], test.wantC)
+		}
+		if i, j, overlap := FirstOverlap(b); overlap {
+			t.Errorf("%s: particles %d and %d overlap after reacting", test.name, i, j)
+		}
+	}
+
+	// diffusing and reacting on a crowded board never leaves two particles overlapping
+	species[0].diffusionRate, species[1].diffusionRate, species[2].diffusionRate = 1, 1, 0.5
+	reactions[0].probability = 0.5
+	b := InitializeSpeciesBoard(150, 150, species, []int{150, 150, 0}, reactions, true, true, 1)
+	boards := UpdateBoards(b, 30, true, 4)
+
+	counts := SpeciesCounts(boards, species)
+	for step, board := range boards {
+		if i, j, overlap := FirstOverlap(board); overlap {
+			t.Fatalf("particles %d and %d overlap after step %d", i, j, step)
+		}
+		if counts[step][0]+counts[step][2] != 150 || counts[step][1]+counts[step][2] != 150 {
+			t.Fatalf("counts after step %d = %v, want each C to replace one A and one B", step, counts[step])
+		}
+	}
+	if counts[30][2] == 0 {
+		t.Errorf("no C was made on the crowded board")
+	}
+}
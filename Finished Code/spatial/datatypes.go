@@ -1,10 +1,16 @@
 package main
 
-// Cell contains a strategy (as a string) and a score (as a decimal).
+// Cell contains a strategy (as an index into the strategies of a Game) and a score (as a decimal).
 type Cell struct {
-	strategy string  //represents "C" or "D" corresponding to the type of prisoner in the cell
+	strategy int     //represents the strategy of the cell, such as 0 for "C" and 1 for "D" in the Prisoner's Dilemma
 	score    float64 //represents the score of the cell based on the prisoner's relationship with neighboring cells
 }
 
+// Game is a symmetric two-player game between any number of strategies.
+type Game struct {
+	names   []string    // names[i] is the single-character name of strategy i, as used in board files
+	payoffs [][]float64 // payoffs[i][j] is the payoff to strategy i when it plays against strategy j
+}
+
 // GameBoard is a 2D slice of Cell objects representing our board.
 type GameBoard [][]Cell
@@ -2,20 +2,124 @@ package main
 
 import (
 	"canvas"
+	"fmt"
+	"glyphs"
 	"image"
+	"image/color"
+	"strconv"
+	"strings"
 )
 
-func BoardsToImages(boards []GameBoard, cellWidth int) []image.Image {
+// strategyColors gives the color of each strategy index, so that in the Prisoner's Dilemma
+// cooperators (0) are blue and defectors (1) are red.
+var strategyColors = []color.Color{
+	canvas.MakeColor(7, 30, 230),    // blue
+	canvas.MakeColor(239, 71, 111),  // red
+	canvas.MakeColor(6, 214, 160),   // green
+	canvas.MakeColor(255, 209, 102), // yellow
+	canvas.MakeColor(150, 60, 200),  // purple
+	canvas.MakeColor(255, 140, 30),  // orange
+	canvas.MakeColor(120, 220, 250), // light blue
+	canvas.MakeColor(240, 240, 240), // white
+}
+
+// StrategyColor takes a strategy index and returns the color used to draw cells with that strategy.
+// Games with more strategies than named colors get light grays for the rest, leaving the darker grays
+// to TransitionColor.
+func StrategyColor(strategy int) color.Color {
+	if strategy < len(strategyColors) {
+		return strategyColors[strategy]
+	}
+	shade := uint8(150 + (strategy*37)%90)
+	return canvas.MakeColor(shade, shade, shade)
+}
+
+// DrawSettings says how boards are drawn.
+type DrawSettings struct {
+	squares     bool // if true, cells are filled squares rather than circles
+	transitions bool // if true, cells are colored by how their strategy changed since the previous board
+	gridLines   bool // if true, lines are drawn between cells
+}
+
+// DefaultDrawSettings returns the original settings, which draw each cell as a circle colored by its strategy.
+func DefaultDrawSettings() DrawSettings {
+	return DrawSettings{}
+}
+
+// ParseDrawSettings takes a string holding a cell width, optionally followed by a colon and
+// a comma-separated list of options ("squares", "transitions", "grid"), such as 5:squares,grid.
+// It returns the cell width and the DrawSettings.
+func ParseDrawSettings(s string) (int, DrawSettings, error) {
+	settings := DefaultDrawSettings()
+	fields := strings.SplitN(s, ":", 2)
+
+	cellWidth, err := strconv.Atoi(fields[0])
+	if err != nil || cellWidth <= 0 {
+		return 0, settings, fmt.Errorf("cell width must be a positive integer, got %s", fields[0])
+	}
+
+	if len(fields) == 2 {
+		for _, option := range strings.Split(fields[1], ",") {
+			switch option {
+			case "squares":
+				settings.squares = true
+			case "transitions":
+				settings.transitions = true
+			case "grid":
+				settings.gridLines = true
+			default:
+				return 0, settings, fmt.Errorf("unknown drawing option %s", option)
+			}
+		}
+	}
+
+	return cellWidth, settings, nil
+}
+
+// TransitionColor takes the strategy of a cell on the previous board and on the current board, and returns the
+// color of the classic transition scheme: blue for C after C, red for D after D, green for C after D, and
+// yellow for D after C. In games with more strategies, any other change is shown by a darker strategy color.
+// The colors of changes are never colors of strategies, so a cell that changed can't be mistaken for one that
+// kept strategy 2 or 3.
+func TransitionColor(previous, current int) color.Color {
+	if previous == current {
+		return StrategyColor(current)
+	}
+	if previous == 1 && current == 0 {
+		return canvas.MakeColor(0, 170, 0) // green
+	}
+	if previous == 0 && current == 1 {
+		return canvas.MakeColor(255, 255, 0) // yellow
+	}
+
+	r, g, b, _ := StrategyColor(current).RGBA()
+	return canvas.MakeColor(uint8(r>>9), uint8(g>>9), uint8(b>>9))
+}
+
+// BoardsToImages takes a slice of GameBoards, a cell width, and DrawSettings, and draws every board.
+// In transition mode, each board is compared with the one before it, and the first board with itself.
+func BoardsToImages(boards []GameBoard, cellWidth int, settings DrawSettings) []image.Image {
 	imageList := make([]image.Image, len(boards))
 	for i := range boards {
-		imageList[i] = boards[i].BoardToImage(cellWidth)
+		previous := boards[i]
+		if i > 0 {
+			previous = boards[i-1]
+		}
+		imageList[i] = boards[i].DrawBoard(previous, cellWidth, settings)
 	}
 	return imageList
 }
 
 // BoardToImage converts a GameBoard to an image, in which
-// each cell has a cell width given by a parameter
+// each cell has a cell width given by a parameter, using the default settings.
 func (g GameBoard) BoardToImage(cellWidth int) image.Image {
+	return g.DrawBoard(g, cellWidth, DefaultDrawSettings())
+}
+
+// DrawBoard is a GameBoard method that takes the previous GameBoard, a cell width, and DrawSettings.
+// It returns an image in which each cell is a square of width cellWidth holding a circle or filling the square,
+// colored by its strategy or, in transition mode, by how its strategy changed since the previous board.
+func (g GameBoard) DrawBoard(previous GameBoard, cellWidth int, settings DrawSettings) image.Image {
 	//Parse out the # of rows and columns in the field
 	rows := len(g)
 	columns := len(g[0])
@@ -25,49 +129,181 @@ func (g GameBoard) BoardToImage(cellWidth int) image.Image {
 	c := canvas.CreateNewCanvas(width, height)
 
 	darkGray := canvas.MakeColor(60, 60, 60)
-	blue := canvas.MakeColor(7, 30, 230)
-	red := canvas.MakeColor(239, 71, 111)
-
-	//set the entire board as black
 
+	//set the entire board as dark gray
 	c.SetFillColor(darkGray)
-	c.ClearRect(0, 0, height, width)
-	c.Clear()
+	c.ClearRect(0, 0, width, height)
 
 	for i := 0; i < rows; i++ {
 		for j := 0; j < columns; j++ {
-			if g[i][j].strategy == "C" {
-				//set fill color to blue
-				c.SetFillColor(blue)
-			} else if g[i][j].strategy == "D" {
-				c.SetFillColor(red)
+			if settings.transitions {
+				c.SetFillColor(TransitionColor(previous[i][j].strategy, g[i][j].strategy))
+			} else {
+				c.SetFillColor(StrategyColor(g[i][j].strategy))
 			}
 
 			x := j * cellWidth
 			y := i * cellWidth
 
-			scalingFactor := 0.8 // to make circle smaller
+			if settings.squares {
+				c.ClearRect(x, y, x+cellWidth, y+cellWidth)
+			} else {
+				// center the circle in the cell so that cells on the edges are not cut off
+				scalingFactor := 0.8 // to make circle smaller
+				centerX := float64(x) + float64(cellWidth)/2
+				centerY := float64(y) + float64(cellWidth)/2
+
+				c.Circle(centerX, centerY, scalingFactor*float64(cellWidth)/2)
+				c.Fill()
+			}
+		}
+	}
+
+	if settings.gridLines {
+		c.SetStrokeColor(darkGray)
+		c.SetLineWidth(1)
+		for i := 0; i <= rows; i++ {
+			c.MoveTo(0, float64(i*cellWidth))
+			c.LineTo(float64(width), float64(i*cellWidth))
+			c.Stroke()
+		}
+		for j := 0; j <= columns; j++ {
+			c.MoveTo(float64(j*cellWidth), 0)
+			c.LineTo(float64(j*cellWidth), float64(height))
+			c.Stroke()
+		}
+	}
+
+	return c.GetImage()
+}
+
+// DrawLabel takes a canvas, a label, the position (x, y) of its top left corner, a scale, and a color, and draws
+// the label in the 3 x 5 font of glyphs. Characters without a glyph are left blank.
+func DrawLabel(c *canvas.Canvas, label string, x, y, scale int, col color.Color) {
+	c.SetFillColor(col)
+	glyphs.Draw(label, x, y, scale, c.ClearRect)
+}
+
+// DrawSweep takes the points of a b sweep along with the b values and densities they were computed at.
+// It returns a plot of the steady-state fraction of cooperators (vertical axis, from 0 to 1) against b
+// (horizontal axis, from the smallest to the largest b value), with one colored line per initial density,
+// in the same order as StrategyColor. Horizontal grid lines mark quarters, ticks mark each b value, and both
+// axes are labelled, with as many b values as fit. A legend to the right gives the initial density of each color.
+func DrawSweep(points []SweepPoint, bValues, densities []float64) image.Image {
+	width, height := 900, 520
+	scale := 2 // the size of a pixel of a glyph
+	black := canvas.MakeColor(0, 0, 0)
+
+	c := canvas.CreateNewCanvas(width, height)
+
+	left, right := 70.0, float64(width)-170
+	top, bottom := 50.0, float64(height)-60
+
+	minB, maxB := bValues[0], bValues[len(bValues)-1]
+	x := func(b float64) float64 {
+		if maxB == minB {
+			return (left + right) / 2
+		}
+		return left + (right-left)*(b-minB)/(maxB-minB)
+	}
+	y := func(fraction float64) float64 {
+		return bottom - (bottom-top)*fraction
+	}
+
+	// grid lines at every quarter of the vertical axis
+	c.SetLineWidth(1)
+	c.SetStrokeColor(canvas.MakeColor(220, 220, 220))
+	for q := 1; q <= 4; q++ {
+		c.MoveTo(left, y(float64(q)/4))
+		c.LineTo(right, y(float64(q)/4))
+		c.Stroke()
+	}
+
+	// axes, with a tick at every b value and every quarter
+	c.SetLineWidth(2)
+	c.SetStrokeColor(black)
+	c.MoveTo(left, top)
+	c.LineTo(left, bottom)
+	c.LineTo(right, bottom)
+	c.Stroke()
+	for _, b := range bValues {
+		c.MoveTo(x(b), bottom)
+		c.LineTo(x(b), bottom+6)
+		c.Stroke()
+	}
+	for q := 0; q <= 4; q++ {
+		c.MoveTo(left-6, y(float64(q)/4))
+		c.LineTo(left, y(float64(q)/4))
+		c.Stroke()
+	}
 
-			c.Circle(float64(x), float64(y), scalingFactor*float64(cellWidth)/2)
-			c.Fill()
+	// label the quarters of the vertical axis, right-aligned next to their ticks
+	for q := 0; q <= 4; q++ {
+		label := strconv.FormatFloat(float64(q)/4, 'f', 2, 64)
+		DrawLabel(&c, label, int(left)-10-glyphs.Width(label, scale), int(y(float64(q)/4))-5*scale/2, scale, black)
+	}
 
-			/*
-				//draw the rectangle
-				x1, y1 := cellWidth*j, cellWidth*i
-				x2, y2 := cellWidth*(j+1), cellWidth*(i+1)
-				c.ClearRect(x1, y1, x2, y2)
-			*/
+	// label the b values, skipping some if they would run into each other
+	step := 1
+	if len(bValues) > 1 {
+		spacing := (right - left) / float64(len(bValues)-1)
+		widest := 0
+		for _, b := range bValues {
+			if w := glyphs.Width(strconv.FormatFloat(b, 'g', 3, 64), scale); w > widest {
+				widest = w
+			}
+		}
+		for float64(step)*spacing < float64(widest+10) {
+			step++
+		}
+	}
+	for i := 0; i < len(bValues); i += step {
+		label := strconv.FormatFloat(bValues[i], 'g', 3, 64)
+		DrawLabel(&c, label, int(x(bValues[i]))-glyphs.Width(label, scale)/2, int(bottom)+12, scale, black)
+	}
 
-			//what if we want to draw circles instead?
-			/*
-				x1, y1 := cellWidth*j, cellWidth*i
-				radius := float64(cellWidth) / 2.0
-				c.Circle(float64(x1), float64(y1), float64(radius))
-			*/
+	// axis titles
+	DrawLabel(&c, "b", int((left+right)/2)-glyphs.Width("b", scale)/2, int(bottom)+36, scale, black)
+	DrawLabel(&c, "cooperation", int(left)-10-glyphs.Width("0.00", scale), int(top)-30, scale, black)
 
-			c.Fill()
+	// one line per density
+	for d, density := range densities {
+		col := StrategyColor(d)
+		c.SetStrokeColor(col)
+		c.SetFillColor(col)
 
+		first := true
+		for _, p := range points {
+			if p.density != density {
+				continue
+			}
+			if first {
+				c.MoveTo(x(p.b), y(p.cooperation))
+				first = false
+			} else {
+				c.LineTo(x(p.b), y(p.cooperation))
+			}
+		}
+		c.Stroke()
+
+		for _, p := range points {
+			if p.density == density {
+				c.Circle(x(p.b), y(p.cooperation), 4)
+				c.Fill()
+			}
 		}
 	}
+
+	// legend: a swatch of each color followed by its initial defector density
+	legendX := int(right) + 30
+	legendY := int(top)
+	DrawLabel(&c, "density", legendX, legendY, scale, black)
+	for d, density := range densities {
+		rowY := legendY + 24*(d+1)
+		c.SetFillColor(StrategyColor(d))
+		c.ClearRect(legendX, rowY, legendX+14, rowY+14)
+		DrawLabel(&c, strconv.FormatFloat(density, 'g', 3, 64), legendX+22, rowY+2, scale, black)
+	}
+
 	return c.GetImage()
 }
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestTransitionColorsDistinct tests that no change of strategy is drawn in the color of a strategy,
+// so that cells that changed stand out from cells that didn't, whatever the number of strategies.
+func TestTransitionColorsDistinct(t *testing.T) {
+	numStrategies := 100
+
+	strategyOf := make(map[color.RGBA]int)
+	for s := 0; s < numStrategies; s++ {
+		strategyOf[color.RGBAModel.Convert(StrategyColor(s)).(color.RGBA)] = s
+	}
+
+	for previous := 0; previous < numStrategies; previous++ {
+		for current := 0; current < numStrategies; current++ {
+			if previous == current {
+				continue
+			}
+			col := color.RGBAModel.Convert(TransitionColor(previous, current)).(color.RGBA)
+			if s, ok := strategyOf[col]; ok {
+				t.Fatalf("change from %d to %d has the color of strategy %d", previous, current, s)
+			}
+		}
+	}
+}
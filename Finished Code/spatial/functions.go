@@ -1,70 +1,77 @@
 package main
 
-//GameBetween computes the contribution of Cell c2 to the score of Cell c1, assuming
-import (
-	"fmt"
-	"os"
-)
-
-//that c2 is in the neighborhood of c1.
-func GameBetween(c1, c2 Cell, b float64) float64 {
-	if c1.strategy == "C" && c2.strategy == "C" {
-		return 1.0
-	} else if c1.strategy == "C" && c2.strategy == "D" {
-		return 0.0
-	} else if c1.strategy == "D" && c2.strategy == "C" {
-		return b
-	} else if c1.strategy == "D" && c2.strategy == "D" {
-		return 0.0
-	} else {
-		fmt.Println("Error: Not all strategies are C or D.")
-		os.Exit(1)
+// GameBetween computes the contribution of Cell c2 to the score of Cell c1, assuming
+// that c2 is in the neighborhood of c1, by looking up the payoff of their strategies in the game.
+func GameBetween(c1, c2 Cell, game Game) float64 {
+	return game.payoffs[c1.strategy][c2.strategy]
+}
+
+// PrisonersDilemma takes a payoff b and returns the weak Prisoner's Dilemma, in which
+// strategy 0 ("C") cooperates and strategy 1 ("D") defects. Two cooperators each earn 1,
+// a defector earns b against a cooperator, and every other pairing earns 0.
+func PrisonersDilemma(b float64) Game {
+	return Game{
+		names:   []string{"C", "D"},
+		payoffs: [][]float64{{1.0, 0.0}, {b, 0.0}},
+	}
+}
+
+// NumStrategies is a Game method that returns the number of strategies in the game.
+func (game Game) NumStrategies() int {
+	return len(game.names)
+}
+
+// StrategyIndex is a Game method that takes the name of a strategy and returns its index,
+// or -1 if the game has no strategy with that name.
+func (game Game) StrategyIndex(name string) int {
+	for i := range game.names {
+		if game.names[i] == name {
+			return i
+		}
 	}
-	return -1.0
+	return -1
 }
 
-// updateScores goes through every cell, and plays the Prisoner's dilemma game
-// with each of its in-field nieghbors (including itself). It updates the
-// score of each cell to be the sum of that cell's winnings from the game.
-func (g2 GameBoard) UpdateScores(g1 GameBoard, b float64) {
+// updateScores goes through every cell, and plays the game with each of the
+// neighbors given by the rules (including itself, if rules.selfPlay is true).
+// It updates the score of each cell to be the sum of that cell's winnings from the game.
+func (g2 GameBoard) UpdateScores(g1 GameBoard, game Game, rules Rules) {
 	//parse out the dimensions of the board
 	rows := len(g2)
 	columns := len(g2[0])
 
+	cellAt := func(k, l int) Cell {
+		return g1[k][l]
+	}
+
 	for i := 0; i < rows; i++ {
 		for j := 0; j < columns; j++ {
-			for k := i - 1; k <= i+1; k++ {
-				for l := j - 1; l <= j+1; l++ {
-					if InField(k, l, rows, columns) && (i != k || j != l) {
-						g2[i][j].score += GameBetween(g1[i][j], g1[k][l], b)
-					}
-				}
-			}
+			g2[i][j].score = rules.CellScore(cellAt, i, j, rows, columns, game)
 		}
 	}
 }
 
 // updateStrategies updates the strategies in g2 based on the scores in g2 and the
-// strategies from g1 that these scores were computed from.
-func (g2 GameBoard) UpdateStrategies(g1 GameBoard) {
+// strategies from g1 that these scores were computed from, using the update rule
+// of the rules. With the default rule, each cell copies the strategy of the highest
+// scoring cell in its neighborhood (including itself, if rules.selfImitation is true),
+// with ties going to the first in row-major order. The generation numbers the random
+// choices of the stochastic rules.
+func (g2 GameBoard) UpdateStrategies(g1 GameBoard, game Game, rules Rules, generation int) {
 
 	// parse out the # of rows and columns of matrix
 	rows := len(g2)
 	columns := len(g2[0])
 
+	// every cell sees the old strategies alongside the new scores
+	cellAt := func(k, l int) Cell {
+		return Cell{strategy: g1[k][l].strategy, score: g2[k][l].score}
+	}
+
 	for i := 0; i < rows; i++ {
 		for j := 0; j < columns; j++ {
-			max := -1.0
-			for k := i - 1; k <= i+1; k++ {
-				for l := j - 1; l <= j+1; l++ {
-					if InField(k, l, rows, columns) {
-						if g2[k][l].score > max {
-							max = g2[k][l].score
-							g2[i][j].strategy = g1[k][l].strategy
-						}
-					}
-				}
-			}
+			r := Stream{seed: rules.seed, generation: generation, index: i*columns + j}
+			g2[i][j].strategy = rules.ChooseStrategy(cellAt, i, j, rows, columns, game, r)
 		}
 	}
 }
@@ -87,20 +94,26 @@ func InField(i, j, rows, columns int) bool {
 
 // Evolve() takes an intial field and evolves it for steps according to the game
 // rule. At each step, it should call "updateScores()" and the updateStrategies
-func (initialBoard GameBoard) Evolve(steps int, b float64) []GameBoard {
+func (initialBoard GameBoard) Evolve(steps int, game Game, rules Rules) []GameBoard {
 	boards := make([]GameBoard, steps+1)
 	boards[0] = initialBoard
 	for i := 1; i <= steps; i++ {
-		boards[i] = Update(boards[i-1], b)
+		boards[i] = Update(boards[i-1], game, rules, i)
 	}
 	return boards
 }
 
-func Update(g1 GameBoard, b float64) GameBoard {
+// Update takes a GameBoard, a Game, the Rules, and the number of the generation being computed,
+// and returns the next GameBoard.
+func Update(g1 GameBoard, game Game, rules Rules, generation int) GameBoard {
+	if rules.asynchronous {
+		return UpdateAsynchronous(g1, game, rules, generation)
+	}
+
 	g2 := CreateBoard(len(g1), len(g1[0]))
 
-	g2.UpdateScores(g1, b)
-	g2.UpdateStrategies(g1)
+	g2.UpdateScores(g1, game, rules)
+	g2.UpdateStrategies(g1, game, rules, generation)
 
 	return g2
 }
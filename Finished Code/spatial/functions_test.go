@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+)
+
+// BoardFromStrategies takes rows of strategy indices and returns the GameBoard holding them, with zero scores.
+func BoardFromStrategies(strategies [][]int) GameBoard {
+	g := CreateBoard(len(strategies), len(strategies[0]))
+	for i := range g {
+		for j := range g[i] {
+			g[i][j].strategy = strategies[i][j]
+		}
+	}
+	return g
+}
+
+// CheckBoard takes a GameBoard along with the scores and strategies expected of it, and reports every cell that differs.
+func CheckBoard(t *testing.T, name string, g GameBoard, scores [][]float64, strategies [][]int) {
+	t.Helper()
+	for i := range g {
+		for j := range g[i] {
+			if g[i][j].score != scores[i][j] || g[i][j].strategy != strategies[i][j] {
+				t.Errorf("%s: cell (%d, %d) has strategy %d and score %g, want strategy %d and score %g",
+					name, i, j, g[i][j].strategy, g[i][j].score, strategies[i][j], scores[i][j])
+			}
+		}
+	}
+}
+
+// TestUpdatePrisonersDilemma tests one generation of the Prisoner's Dilemma with a single defector in the center of a
+// 5 x 5 board under the default rules. A cooperator earns 1 from each cooperating neighbor and the defector earns
+// b = 1.5 from each of its eight, so the defector spreads to its neighbors but no further.
+func TestUpdatePrisonersDilemma(t *testing.T) {
+	g := BoardFromStrategies([][]int{
+		{0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0},
+	})
+
+	scores := [][]float64{
+		{3, 5, 5, 5, 3},
+		{5, 7, 7, 7, 5},
+		{5, 7, 12, 7, 5},
+		{5, 7, 7, 7, 5},
+		{3, 5, 5, 5, 3},
+	}
+	strategies := [][]int{
+		{0, 0, 0, 0, 0},
+		{0, 1, 1, 1, 0},
+		{0, 1, 1, 1, 0},
+		{0, 1, 1, 1, 0},
+		{0, 0, 0, 0, 0},
+	}
+
+	CheckBoard(t, "Prisoner's Dilemma", Update(g, PrisonersDilemma(1.5), DefaultRules(), 1), scores, strategies)
+}
+
+// TestUpdateRockPaperScissors tests one generation of rock-paper-scissors, which has three strategies, on the row
+// R S P. Rock beats scissors, and the ties in score between rock and scissors go to the first in row-major order.
+func TestUpdateRockPaperScissors(t *testing.T) {
+	game := Game{
+		names:   []string{"R", "P", "S"},
+		payoffs: [][]float64{{0.5, 0, 1}, {1, 0.5, 0}, {0, 1, 0.5}},
+	}
+	g := BoardFromStrategies([][]int{{0, 2, 1}})
+
+	// R beats S for 1; S loses to R for 0 and beats P for 1; P loses to S for 0
+	scores := [][]float64{{1, 1, 0}}
+	strategies := [][]int{{0, 0, 2}}
+	CheckBoard(t, "rock-paper-scissors", Update(g, game, DefaultRules(), 1), scores, strategies)
+
+	// playing against itself adds the tie payoff of 0.5 to every score
+	rules := DefaultRules()
+	rules.selfPlay = true
+	CheckBoard(t, "rock-paper-scissors with self-play", Update(g, game, rules, 1), [][]float64{{1.5, 1.5, 0.5}}, strategies)
+}
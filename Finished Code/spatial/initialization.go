@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"rng"
+	"strconv"
+	"strings"
+)
+
+// RandomBoard takes a number of rows and columns, a defector density, and a seed.
+// It returns a board of the Prisoner's Dilemma in which each cell independently defects (strategy 1)
+// with probability defectorDensity and cooperates (strategy 0) otherwise.
+// The board draws from index 0 of the seed, which the stochastic rules never use since they start at generation 1.
+func RandomBoard(rows, columns int, defectorDensity float64, seed int64) GameBoard {
+	source := rng.New(rng.DeriveSeed(seed, 0))
+
+	g := CreateBoard(rows, columns)
+	for i := range g {
+		for j := range g[i] {
+			if source.Float64() < defectorDensity {
+				g[i][j].strategy = 1
+			}
+		}
+	}
+
+	return g
+}
+
+// RandomStrategyBoard takes a number of rows and columns, a number of strategies, and a seed.
+// It returns a board in which each cell plays a strategy chosen uniformly at random.
+func RandomStrategyBoard(rows, columns, numStrategies int, seed int64) GameBoard {
+	source := rng.New(rng.DeriveSeed(seed, 0))
+
+	g := CreateBoard(rows, columns)
+	for i := range g {
+		for j := range g[i] {
+			g[i][j].strategy = source.Intn(numStrategies)
+		}
+	}
+
+	return g
+}
+
+// SingleDefectorBoard takes a number of rows and columns and returns the classic board of cooperators
+// with a single defector in the center, which grows into the kaleidoscopes of Nowak and May for odd dimensions.
+func SingleDefectorBoard(rows, columns int) GameBoard {
+	g := CreateBoard(rows, columns)
+	g[rows/2][columns/2].strategy = 1
+	return g
+}
+
+// MakeBoard takes a description of an initial board, a Game, and a seed, and returns the board it describes.
+// The description is one of
+//    single:ROWSxCOLUMNS            a single defector in the center
+//    random:ROWSxCOLUMNS:DENSITY    defectors placed at random with the given density
+//    uniform:ROWSxCOLUMNS           every strategy of the game equally likely
+// and anything else is read as the name of a board file.
+func MakeBoard(description string, game Game, seed int64) (GameBoard, error) {
+	fields := strings.Split(description, ":")
+
+	switch fields[0] {
+	case "single", "random", "uniform":
+	default:
+		return ReadBoardFromFile(description, game)
+	}
+
+	if (fields[0] == "random") != (len(fields) == 3) || len(fields) < 2 || len(fields) > 3 {
+		return nil, fmt.Errorf("badly formed board description %s", description)
+	}
+
+	rows, columns, err := ParseDimensions(fields[1])
+	if err != nil {
+		return nil, err
+	}
+
+	switch fields[0] {
+	case "single":
+		if game.NumStrategies() < 2 {
+			return nil, fmt.Errorf("a single defector board needs a game with at least two strategies")
+		}
+		return SingleDefectorBoard(rows, columns), nil
+	case "random":
+		if game.NumStrategies() < 2 {
+			return nil, fmt.Errorf("a random defector board needs a game with at least two strategies")
+		}
+		density, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil || density < 0 || density > 1 {
+			return nil, fmt.Errorf("defector density must be between 0 and 1, got %s", fields[2])
+		}
+		return RandomBoard(rows, columns, density, seed), nil
+	}
+
+	return RandomStrategyBoard(rows, columns, game.NumStrategies(), seed), nil
+}
+
+// ParseDimensions takes a string of the form ROWSxCOLUMNS and returns the two positive integers it holds.
+func ParseDimensions(s string) (int, int, error) {
+	parts := strings.Split(s, "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("dimensions should look like 100x100, got %s", s)
+	}
+
+	rows, err1 := strconv.Atoi(parts[0])
+	columns, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || rows <= 0 || columns <= 0 {
+		return 0, 0, fmt.Errorf("dimensions must be positive integers, got %s", s)
+	}
+
+	return rows, columns, nil
+}
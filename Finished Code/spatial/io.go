@@ -3,6 +3,8 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"image"
+	"image/png"
 	"os"
 	"strconv"
 	"strings"
@@ -10,19 +12,19 @@ import (
 
 // ReadBoardFromFile should open the given file and read the initial
 // values for the field. The first line of the file will contain
-// two space-separated integers saying how many rows and columns
+// two whitespace-separated positive integers saying how many rows and columns
 // the field should have:
 //    10 15
-// each subsequent line will consist of a string of Cs and Ds, which
-// are the initial strategies for the cells:
+// each subsequent line will consist of a string of strategy names from the
+// given game, such as Cs and Ds, which are the initial strategies for the cells:
 //    CCCCCCDDDCCCCCC
-func ReadBoardFromFile(filename string) GameBoard {
+// Trailing whitespace and blank lines at the end of the file are ignored.
+// An error is returned if the file can't be read or doesn't match this format.
+func ReadBoardFromFile(filename string, game Game) (GameBoard, error) {
 	in, err := os.Open(filename)
-
 	if err != nil {
-		fmt.Println("Error: couldn't open the file!")
+		return nil, fmt.Errorf("couldn't open board file %s: %v", filename, err)
 	}
-
 	defer in.Close()
 
 	// we will store the lines of the file as a slice of strings
@@ -32,30 +34,249 @@ func ReadBoardFromFile(filename string) GameBoard {
 	scanner := bufio.NewScanner(in)
 
 	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read board file %s: %v", filename, err)
+	}
+
+	// drop blank lines at the end of the file
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("board file %s is empty", filename)
 	}
 
 	//Parse out the data in the first line of the file
-	var params []string = strings.Split(lines[0], " ")
+	params := strings.Fields(lines[0])
+	if len(params) != 2 {
+		return nil, fmt.Errorf("first line of %s should hold the number of rows and columns, got %q", filename, lines[0])
+	}
 
 	rows, err1 := strconv.Atoi(params[0])
 	columns, err2 := strconv.Atoi(params[1])
 
-	if err1 != nil || err2 != nil {
-		fmt.Println("Error: row and column must not be numbers.")
-		os.Exit(1)
+	if err1 != nil || err2 != nil || rows <= 0 || columns <= 0 {
+		return nil, fmt.Errorf("rows and columns in %s must be positive integers, got %q", filename, lines[0])
+	}
+
+	if len(lines)-1 != rows {
+		return nil, fmt.Errorf("board file %s says it has %d rows but has %d", filename, rows, len(lines)-1)
 	}
 
 	// Initialize the game board
 	g := CreateBoard(rows, columns)
 
 	// Parse the remaining lines of the data and enter them into the cells
-
 	for i := 0; i < rows; i++ {
+		line := lines[i+1]
+		if len(line) != columns {
+			return nil, fmt.Errorf("row %d of %s has %d cells, expected %d", i+1, filename, len(line), columns)
+		}
+
 		for j := 0; j < columns; j++ {
-			g[i][j].strategy = lines[i+1][j : j+1] //make sure that it has type string
-			g[i][j].score = 0.0                    // default behavior, redundant
+			g[i][j].strategy = game.StrategyIndex(line[j : j+1])
+			if g[i][j].strategy == -1 {
+				return nil, fmt.Errorf("unknown strategy %q at row %d, column %d of %s", line[j:j+1], i+1, j+1, filename)
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// WriteBoardToFile takes a GameBoard, the Game it is played with, and a file name.
+// It writes the strategies of the board to the file in the format read by ReadBoardFromFile.
+func WriteBoardToFile(g GameBoard, game Game, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("couldn't create board file %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintf(writer, "%d %d\n", len(g), len(g[0]))
+	for i := range g {
+		for j := range g[i] {
+			writer.WriteString(game.names[g[i][j].strategy])
+		}
+		writer.WriteString("\n")
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return fmt.Errorf("couldn't write board file %s: %v", filename, err)
+	}
+
+	return nil
+}
+
+// WriteGameToFile takes a Game and a file name, and writes the Game in the format read by ReadGameFromFile.
+func WriteGameToFile(game Game, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("couldn't create game file %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, strings.Join(game.names, " "))
+	for i := range game.payoffs {
+		fields := make([]string, len(game.payoffs[i]))
+		for j, p := range game.payoffs[i] {
+			fields[j] = strconv.FormatFloat(p, 'g', -1, 64)
 		}
+		fmt.Fprintln(writer, strings.Join(fields, " "))
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return fmt.Errorf("couldn't write game file %s: %v", filename, err)
+	}
+
+	return nil
+}
+
+// ReadGameFromFile takes the name of a file holding a payoff matrix and returns the corresponding Game.
+// Blank lines and lines starting with # are ignored. The first remaining line holds the space-separated
+// single-character names of the n strategies, and each of the next n lines holds n payoffs, so that
+// the j-th number on line i is the payoff to strategy i when it plays against strategy j:
+//    C D
+//    1 0
+//    1.85 0
+func ReadGameFromFile(filename string) (Game, error) {
+	var game Game
+
+	in, err := os.Open(filename)
+	if err != nil {
+		return game, fmt.Errorf("couldn't open game file %s: %v", filename, err)
+	}
+	defer in.Close()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return game, fmt.Errorf("couldn't read game file %s: %v", filename, err)
+	}
+
+	if len(lines) == 0 {
+		return game, fmt.Errorf("game file %s has no strategies", filename)
+	}
+
+	game.names = strings.Fields(lines[0])
+	n := len(game.names)
+
+	for i, name := range game.names {
+		if len(name) != 1 {
+			return game, fmt.Errorf("strategy name %q in %s is not a single character", name, filename)
+		}
+		if game.StrategyIndex(name) != i {
+			return game, fmt.Errorf("strategy name %q appears twice in %s", name, filename)
+		}
+	}
+
+	if len(lines) != n+1 {
+		return game, fmt.Errorf("game file %s has %d strategies but %d rows of payoffs", filename, n, len(lines)-1)
+	}
+
+	game.payoffs = make([][]float64, n)
+	for i := range game.payoffs {
+		fields := strings.Fields(lines[i+1])
+		if len(fields) != n {
+			return game, fmt.Errorf("row %d of payoffs in %s has %d entries, expected %d", i+1, filename, len(fields), n)
+		}
+
+		game.payoffs[i] = make([]float64, n)
+		for j := range fields {
+			game.payoffs[i][j], err = strconv.ParseFloat(fields[j], 64)
+			if err != nil {
+				return game, fmt.Errorf("bad payoff %q in row %d of %s", fields[j], i+1, filename)
+			}
+		}
+	}
+
+	return game, nil
+}
+
+// WriteStatsToFile takes the statistics of a simulation, the Game it was run with, and a file name.
+// It writes one line per generation to a CSV file, giving the fraction and mean score of every strategy
+// and the number of strategy changes.
+func WriteStatsToFile(stats []GenerationStats, game Game, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprint(writer, "generation")
+	for _, name := range game.names {
+		fmt.Fprintf(writer, ",fraction%s", name)
+	}
+	for _, name := range game.names {
+		fmt.Fprintf(writer, ",meanScore%s", name)
+	}
+	fmt.Fprintln(writer, ",changes")
+
+	for _, s := range stats {
+		fmt.Fprint(writer, s.generation)
+		for _, f := range s.fractions {
+			fmt.Fprintf(writer, ",%g", f)
+		}
+		for _, m := range s.meanScores {
+			fmt.Fprintf(writer, ",%g", m)
+		}
+		fmt.Fprintf(writer, ",%d\n", s.changes)
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// WriteSweepToFile takes the points of a b sweep and a file name, and writes one line per point to a CSV file.
+func WriteSweepToFile(points []SweepPoint, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, "b,defectorDensity,cooperation,stdDev")
+	for _, p := range points {
+		fmt.Fprintf(writer, "%g,%g,%g,%g\n", p.b, p.density, p.cooperation, p.stdDev)
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SaveImageToPNG takes an image and a file name and writes the image to a PNG file.
+func SaveImageToPNG(img image.Image, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	err = png.Encode(file, img)
+	if err != nil {
+		panic(err)
 	}
-	return g
 }
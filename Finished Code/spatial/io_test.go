@@ -0,0 +1,180 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// WriteTempFile takes a directory, a file name, and the contents of a file, writes the file, and returns its path.
+func WriteTempFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	filename := filepath.Join(dir, name)
+	if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+		t.Fatalf("couldn't write %s: %v", filename, err)
+	}
+	return filename
+}
+
+// TestReadBoardFromFile tests that ReadBoardFromFile reads a well-formed board, ignoring trailing whitespace and
+// blank lines, and returns an error for every kind of malformed board file.
+func TestReadBoardFromFile(t *testing.T) {
+	dir := t.TempDir()
+	game := PrisonersDilemma(1.85)
+
+	g, err := ReadBoardFromFile(WriteTempFile(t, dir, "good.txt", "2 3\r\nCDC  \nDDC\n\n\n"), game)
+	if err != nil {
+		t.Fatalf("couldn't read a good board: %v", err)
+	}
+	want := [][]int{{0, 1, 0}, {1, 1, 0}}
+	for i := range want {
+		for j := range want[i] {
+			if g[i][j].strategy != want[i][j] {
+				t.Errorf("cell (%d, %d) has strategy %d, want %d", i, j, g[i][j].strategy, want[i][j])
+			}
+		}
+	}
+
+	bad := map[string]string{
+		"empty":            "",
+		"blank":            "\n\n",
+		"one dimension":    "2\nCC\nCC\n",
+		"not a number":     "2 x\nCC\nCC\n",
+		"zero rows":        "0 2\n",
+		"negative columns": "2 -2\nCC\nCC\n",
+		"too few rows":     "3 2\nCC\nCC\n",
+		"too many rows":    "1 2\nCC\nCC\n",
+		"ragged short":     "2 3\nCCC\nCC\n",
+		"ragged long":      "2 3\nCCC\nCCCC\n",
+		"unknown strategy": "2 2\nCD\nCX\n",
+		"blank row":        "2 2\nCC\n\nCC\n",
+	}
+	for name, contents := range bad {
+		if _, err := ReadBoardFromFile(WriteTempFile(t, dir, "bad.txt", contents), game); err == nil {
+			t.Errorf("%s: ReadBoardFromFile returned no error for %q", name, contents)
+		}
+	}
+
+	if _, err := ReadBoardFromFile(filepath.Join(dir, "missing.txt"), game); err == nil {
+		t.Errorf("ReadBoardFromFile returned no error for a missing file")
+	}
+}
+
+// TestBoardRoundTrip tests that a board written by WriteBoardToFile is read back unchanged, for a game with three strategies.
+func TestBoardRoundTrip(t *testing.T) {
+	game := Game{
+		names:   []string{"R", "P", "S"},
+		payoffs: [][]float64{{0.5, 0, 1}, {1, 0.5, 0}, {0, 1, 0.5}},
+	}
+	g := RandomStrategyBoard(7, 11, 3, 4)
+	filename := filepath.Join(t.TempDir(), "board.txt")
+
+	if err := WriteBoardToFile(g, game, filename); err != nil {
+		t.Fatalf("WriteBoardToFile returned %v", err)
+	}
+	read, err := ReadBoardFromFile(filename, game)
+	if err != nil {
+		t.Fatalf("ReadBoardFromFile returned %v", err)
+	}
+
+	if len(read) != len(g) || len(read[0]) != len(g[0]) {
+		t.Fatalf("read a %d x %d board, want %d x %d", len(read), len(read[0]), len(g), len(g[0]))
+	}
+	for i := range g {
+		for j := range g[i] {
+			if read[i][j].strategy != g[i][j].strategy {
+				t.Errorf("cell (%d, %d) has strategy %d, want %d", i, j, read[i][j].strategy, g[i][j].strategy)
+			}
+		}
+	}
+}
+
+// TestReadGameFromFile tests that ReadGameFromFile skips comments and blank lines, and returns an error for every
+// kind of malformed game file.
+func TestReadGameFromFile(t *testing.T) {
+	dir := t.TempDir()
+
+	game, err := ReadGameFromFile(WriteTempFile(t, dir, "good.txt", "# a comment\n\nC D\n1 0\n\n# another\n1.85 0\n"))
+	if err != nil {
+		t.Fatalf("couldn't read a good game: %v", err)
+	}
+	if game.NumStrategies() != 2 || game.payoffs[1][0] != 1.85 || game.payoffs[0][0] != 1 {
+		t.Errorf("read %+v, want the Prisoner's Dilemma with b = 1.85", game)
+	}
+
+	bad := map[string]string{
+		"empty":             "",
+		"only comments":     "# nothing\n# here\n",
+		"long name":         "C DD\n1 0\n0 1\n",
+		"repeated name":     "C C\n1 0\n0 1\n",
+		"too few rows":      "C D\n1 0\n",
+		"too many rows":     "C D\n1 0\n0 1\n1 1\n",
+		"ragged row":        "C D\n1 0 2\n0 1\n",
+		"short row":         "C D\n1\n0 1\n",
+		"not a number":      "C D\n1 zero\n0 1\n",
+		"no payoffs at all": "C D\n",
+	}
+	for name, contents := range bad {
+		if _, err := ReadGameFromFile(WriteTempFile(t, dir, "bad.txt", contents)); err == nil {
+			t.Errorf("%s: ReadGameFromFile returned no error for %q", name, contents)
+		}
+	}
+
+	if _, err := ReadGameFromFile(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Errorf("ReadGameFromFile returned no error for a missing file")
+	}
+}
+
+// TestGameRoundTrip tests that games written by WriteGameToFile are read back unchanged, including payoffs that
+// aren't whole numbers.
+func TestGameRoundTrip(t *testing.T) {
+	games := []Game{
+		PrisonersDilemma(1.85),
+		{names: []string{"R", "P", "S"}, payoffs: [][]float64{{0.5, 0, 1}, {1, 0.5, 0}, {0, 1, 0.5}}},
+		{names: []string{"H", "D"}, payoffs: [][]float64{{-1.25, 2}, {0, 1.0 / 3.0}}},
+	}
+
+	for k, game := range games {
+		filename := filepath.Join(t.TempDir(), "game.txt")
+		if err := WriteGameToFile(game, filename); err != nil {
+			t.Fatalf("WriteGameToFile returned %v", err)
+		}
+		read, err := ReadGameFromFile(filename)
+		if err != nil {
+			t.Fatalf("game %d: ReadGameFromFile returned %v", k, err)
+		}
+
+		if read.NumStrategies() != game.NumStrategies() {
+			t.Fatalf("game %d: read %d strategies, want %d", k, read.NumStrategies(), game.NumStrategies())
+		}
+		for i := range game.names {
+			if read.names[i] != game.names[i] {
+				t.Errorf("game %d: strategy %d is named %s, want %s", k, i, read.names[i], game.names[i])
+			}
+			for j := range game.payoffs[i] {
+				if read.payoffs[i][j] != game.payoffs[i][j] {
+					t.Errorf("game %d: payoff (%d, %d) = %g, want %g", k, i, j, read.payoffs[i][j], game.payoffs[i][j])
+				}
+			}
+		}
+	}
+}
+
+// TestRandomBoardSeed tests that random boards are the same for the same seed and differ between seeds.
+func TestRandomBoardSeed(t *testing.T) {
+	a, b, c := RandomBoard(15, 15, 0.4, 9), RandomBoard(15, 15, 0.4, 9), RandomBoard(15, 15, 0.4, 10)
+	differs := false
+	for i := range a {
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				t.Fatalf("cell (%d, %d) differs between boards with the same seed", i, j)
+			}
+			if a[i][j] != c[i][j] {
+				differs = true
+			}
+		}
+	}
+	if !differs {
+		t.Errorf("seeds 9 and 10 gave the same board")
+	}
+}
@@ -1,34 +1,192 @@
 package main
 
 import (
+	"fmt"
 	"gifhelper"
 	"os"
+	"runtime"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func main() {
+	// "sweep" as the first CLA runs the Prisoner's Dilemma over a range of b values and initial densities
+	if len(os.Args) > 1 && os.Args[1] == "sweep" {
+		RunSweep()
+		return
+	}
+
+	// CLAs: board file (or a description of a generated board, as in MakeBoard), b or game file, number of steps,
+	// cell width (with optional drawing options), optionally
+	// neighborhood, radius, and boundary, and then optionally update rule, temperature,
+	// mutation rate, seed, and scheme
+	if len(os.Args) != 5 && len(os.Args) != 8 && len(os.Args) != 13 {
+		fmt.Println("Error: incorrect number of command line arguments.")
+		os.Exit(1)
+	}
+
 	filename := os.Args[1]
 
+	// the second CLA is either the payoff b of the Prisoner's Dilemma or the name of a payoff matrix file
+	var game Game
 	b, err := strconv.ParseFloat(os.Args[2], 64)
+	if err == nil {
+		game = PrisonersDilemma(b)
+	} else {
+		game, err = ReadGameFromFile(os.Args[2])
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	steps, err := strconv.Atoi(os.Args[3])
 	if err != nil {
 		os.Exit(1)
 	}
 
-	steps, err := strconv.Atoi(os.Args[3])
+	// the cell width may be followed by drawing options, as in ParseDrawSettings
+	cellWidth, drawSettings, err := ParseDrawSettings(os.Args[4])
 	if err != nil {
+		fmt.Println("Error:", err)
 		os.Exit(1)
 	}
 
-	cellWidth, err := strconv.Atoi(os.Args[4])
+	// optional CLAs: neighborhood ("moore", "vonneumann", or "hexagonal", with optional self-interaction
+	// options, as in ParseNeighborhood), its radius, and boundary ("fixed", "toroidal", or "reflecting")
+	rules := DefaultRules()
+	if len(os.Args) >= 8 {
+		radius, err := strconv.Atoi(os.Args[6])
+		if err != nil {
+			os.Exit(1)
+		}
+
+		rules, err = ParseNeighborhood(os.Args[5], radius, rules)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+
+		rules.boundary, err = BoundaryByName(os.Args[7])
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	// optional CLAs: update rule ("best", "fermi", or "proportional"), temperature of Fermi imitation,
+	// mutation rate, random seed, and scheme ("sync" or "async")
+	if len(os.Args) == 13 {
+		rules.update, err = UpdateRuleByName(os.Args[8])
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+
+		rules.temperature, err = strconv.ParseFloat(os.Args[9], 64)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		rules.mutationRate, err = strconv.ParseFloat(os.Args[10], 64)
+		if err != nil || rules.mutationRate < 0 || rules.mutationRate > 1 {
+			fmt.Println("Error: mutation rate must be between 0 and 1.")
+			os.Exit(1)
+		}
+
+		rules.seed, err = strconv.ParseInt(os.Args[11], 10, 64)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		if os.Args[12] == "async" {
+			rules.asynchronous = true
+		} else if os.Args[12] != "sync" {
+			fmt.Println("Error: scheme must be sync or async.")
+			os.Exit(1)
+		}
+	}
+
+	initialBoard, err := MakeBoard(filename, game, rules.seed)
 	if err != nil {
+		fmt.Println("Error:", err)
 		os.Exit(1)
 	}
 
-	initialBoard := ReadBoardFromFile(filename)
+	boards := initialBoard.EvolveParallel(steps, game, rules, runtime.NumCPU())
+
+	// record the statistics of every generation
+	stats := ComputeStats(boards, game, rules)
+	WriteStatsToFile(stats, game, "prisoners_stats.csv")
+	fmt.Println("Statistics of every generation written to prisoners_stats.csv.")
+
+	// save the final board so that it can be used as the start of another run
+	err = WriteBoardToFile(boards[len(boards)-1], game, "prisoners_final.txt")
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	fmt.Println("Final board written to prisoners_final.txt.")
 
-	boards := initialBoard.Evolve(steps, b)
+	// save the game alongside the results, so that the run can be repeated with it as the game file
+	err = WriteGameToFile(game, "prisoners_game.txt")
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	fmt.Println("Game written to prisoners_game.txt.")
 
 	// generate the GIF
-	imageList := BoardsToImages(boards, cellWidth)
+	imageList := BoardsToImages(boards, cellWidth, drawSettings)
 	gifhelper.ImagesToGIF(imageList, "prisoners")
 }
+
+// RunSweep parses the CLAs of the sweep command, runs the Prisoner's Dilemma on random boards for every
+// pair of b value and initial defector density, and writes the steady-state cooperation to sweep.csv and sweep.png.
+func RunSweep() {
+	// CLAs: sweep, board size, number of steps, minimum b, maximum b, number of b values,
+	// comma-separated initial defector densities, number of trials
+	if len(os.Args) != 9 {
+		fmt.Println("Error: incorrect number of command line arguments for sweep.")
+		os.Exit(1)
+	}
+
+	size, err1 := strconv.Atoi(os.Args[2])
+	steps, err2 := strconv.Atoi(os.Args[3])
+	minB, err3 := strconv.ParseFloat(os.Args[4], 64)
+	maxB, err4 := strconv.ParseFloat(os.Args[5], 64)
+	numB, err5 := strconv.Atoi(os.Args[6])
+	trials, err6 := strconv.Atoi(os.Args[8])
+
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil || err5 != nil || err6 != nil {
+		fmt.Println("Error: couldn't parse the command line arguments for sweep.")
+		os.Exit(1)
+	}
+
+	if size <= 0 || steps <= 0 || numB <= 0 || trials <= 0 || maxB < minB {
+		fmt.Println("Error: size, steps, number of b values, and trials must be positive, and maxB at least minB.")
+		os.Exit(1)
+	}
+
+	densities := make([]float64, 0)
+	for _, field := range strings.Split(os.Args[7], ",") {
+		density, err := strconv.ParseFloat(field, 64)
+		if err != nil || density < 0 || density > 1 {
+			fmt.Println("Error: densities must be comma-separated numbers between 0 and 1.")
+			os.Exit(1)
+		}
+		densities = append(densities, density)
+	}
+
+	bValues := LinearRange(minB, maxB, numB)
+
+	start := time.Now()
+	points := SweepB(size, steps, bValues, densities, trials, DefaultRules(), runtime.NumCPU())
+	fmt.Printf("Sweeping %d points took %s\n", len(points), time.Since(start))
+
+	WriteSweepToFile(points, "sweep.csv")
+	SaveImageToPNG(DrawSweep(points, bValues, densities), "sweep.png")
+
+	fmt.Printf("Steady-state cooperation written to sweep.csv and plotted against b from %g to %g in sweep.png.\n", minB, maxB)
+}
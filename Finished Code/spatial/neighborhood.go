@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"stencil"
+	"strings"
+)
+
+// Neighborhood is a list of (row, column) offsets from a cell to the cells in its neighborhood.
+// The offsets are listed in row-major order and include the cell itself at (0, 0);
+// whether a cell interacts with itself is decided by the Rules, not the Neighborhood.
+type Neighborhood [][2]int
+
+// Boundary says how neighbors that fall off the edge of the board are handled.
+type Boundary int
+
+const (
+	Fixed      Boundary = iota // cells off the board are missing
+	Toroidal                   // the board wraps around in both directions
+	Reflecting                 // the board is mirrored across its edges, so a cell off the board is the image of one on it
+)
+
+// Rules collects the options that decide who plays whom and who imitates whom in the spatial game.
+type Rules struct {
+	neighborhood  Neighborhood
+	boundary      Boundary
+	selfPlay      bool // if true, a cell also plays the game against itself when scoring
+	selfImitation bool // if true, a cell's own score competes with its neighbors' when choosing a new strategy
+
+	update       UpdateRule // how a cell chooses its new strategy
+	temperature  float64    // the noise K of Fermi imitation
+	mutationRate float64    // the probability that a cell switches to a uniformly random strategy after updating
+	asynchronous bool       // if true, cells update one at a time in random order rather than all at once
+	seed         int64      // the seed of the random numbers used by stochastic rules
+}
+
+// DefaultRules returns the original rules of the spatial game: the 3x3 Moore neighborhood with fixed edges,
+// where a cell does not play against itself but does keep its own strategy if it scores best,
+// and every cell synchronously copies the best scoring strategy in its neighborhood without mutation.
+func DefaultRules() Rules {
+	return Rules{
+		neighborhood:  Moore(1),
+		boundary:      Fixed,
+		selfPlay:      false,
+		selfImitation: true,
+		update:        BestNeighbor,
+		temperature:   0.1,
+		mutationRate:  0.0,
+		asynchronous:  false,
+		seed:          1,
+	}
+}
+
+// Moore takes a radius r and returns the (2r+1) x (2r+1) square neighborhood.
+func Moore(r int) Neighborhood {
+	n := make(Neighborhood, 0)
+	for di := -r; di <= r; di++ {
+		for dj := -r; dj <= r; dj++ {
+			n = append(n, [2]int{di, dj})
+		}
+	}
+	return n
+}
+
+// VonNeumann takes a radius r and returns the diamond of cells within Manhattan distance r.
+func VonNeumann(r int) Neighborhood {
+	n := make(Neighborhood, 0)
+	for di := -r; di <= r; di++ {
+		for dj := -r; dj <= r; dj++ {
+			if Abs(di)+Abs(dj) <= r {
+				n = append(n, [2]int{di, dj})
+			}
+		}
+	}
+	return n
+}
+
+// Hexagonal returns the six-neighbor hexagonal neighborhood. The board is treated as a hexagonal lattice
+// sheared onto the square grid, so the neighbors of a cell are its Moore neighbors except for the
+// up-right and down-left diagonals.
+func Hexagonal() Neighborhood {
+	n := make(Neighborhood, 0)
+	for _, offset := range Moore(1) {
+		if offset != [2]int{-1, 1} && offset != [2]int{1, -1} {
+			n = append(n, offset)
+		}
+	}
+	return n
+}
+
+// NeighborhoodByName takes the name of a neighborhood ("moore", "vonneumann", or "hexagonal") and a radius,
+// and returns that Neighborhood. The radius is ignored by the hexagonal neighborhood.
+func NeighborhoodByName(name string, r int) (Neighborhood, error) {
+	if r < 1 {
+		return nil, fmt.Errorf("neighborhood radius must be positive, got %d", r)
+	}
+
+	switch name {
+	case "moore":
+		return Moore(r), nil
+	case "vonneumann":
+		return VonNeumann(r), nil
+	case "hexagonal":
+		return Hexagonal(), nil
+	}
+
+	return nil, fmt.Errorf("unknown neighborhood %s", name)
+}
+
+// ParseNeighborhood takes a string holding the name of a neighborhood, optionally followed by a colon and a
+// comma-separated list of self-interaction options ("selfplay", "noselfplay", "selfimitation", "noselfimitation"),
+// such as moore:selfplay,noselfimitation, along with a radius and a Rules object. It returns a copy of the Rules
+// with that neighborhood, and with selfPlay and selfImitation changed as the options say.
+func ParseNeighborhood(s string, r int, rules Rules) (Rules, error) {
+	fields := strings.SplitN(s, ":", 2)
+
+	neighborhood, err := NeighborhoodByName(fields[0], r)
+	if err != nil {
+		return rules, err
+	}
+	rules.neighborhood = neighborhood
+
+	if len(fields) == 2 {
+		for _, option := range strings.Split(fields[1], ",") {
+			switch option {
+			case "selfplay":
+				rules.selfPlay = true
+			case "noselfplay":
+				rules.selfPlay = false
+			case "selfimitation":
+				rules.selfImitation = true
+			case "noselfimitation":
+				rules.selfImitation = false
+			default:
+				return rules, fmt.Errorf("unknown self-interaction option %s", option)
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+// BoundaryByName takes the name of a boundary ("fixed", "toroidal", or "reflecting") and returns that Boundary.
+func BoundaryByName(name string) (Boundary, error) {
+	switch name {
+	case "fixed":
+		return Fixed, nil
+	case "toroidal":
+		return Toroidal, nil
+	case "reflecting":
+		return Reflecting, nil
+	}
+
+	return Fixed, fmt.Errorf("unknown boundary %s", name)
+}
+
+// ApplyBoundary takes an index i along a dimension of length n and a Boundary.
+// It returns the index on the board that i refers to, and false if i is off the board under a fixed boundary.
+func ApplyBoundary(i, n int, boundary Boundary) (int, bool) {
+	if i >= 0 && i < n {
+		return i, true
+	}
+
+	switch boundary {
+	case Toroidal:
+		return stencil.Wrap(i, n), true
+	case Reflecting:
+		// mirror across the edges, so that -1 is the image of 0 and n is the image of n-1
+		m := stencil.Wrap(i, 2*n)
+		if m >= n {
+			m = 2*n - 1 - m
+		}
+		return m, true
+	}
+
+	return i, false
+}
+
+// Neighbor is a Rules method that takes a cell (i, j), an offset from the Neighborhood, and the dimensions of the board.
+// It returns the position of the neighbor at that offset after applying the boundary, and false if there is none.
+func (rules Rules) Neighbor(i, j int, offset [2]int, rows, columns int) (int, int, bool) {
+	k, ok1 := ApplyBoundary(i+offset[0], rows, rules.boundary)
+	l, ok2 := ApplyBoundary(j+offset[1], columns, rules.boundary)
+	return k, l, ok1 && ok2
+}
+
+// Abs returns the absolute value of an integer.
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
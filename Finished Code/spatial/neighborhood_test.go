@@ -0,0 +1,144 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestNeighborhoods tests the sizes of the neighborhoods, that each lists its offsets in row-major order including
+// the cell itself, and which offsets the smaller ones hold.
+func TestNeighborhoods(t *testing.T) {
+	tests := []struct {
+		name         string
+		neighborhood Neighborhood
+		size         int
+	}{
+		{"Moore(1)", Moore(1), 9},
+		{"Moore(2)", Moore(2), 25},
+		{"VonNeumann(1)", VonNeumann(1), 5},
+		{"VonNeumann(2)", VonNeumann(2), 13},
+		{"Hexagonal", Hexagonal(), 7},
+	}
+
+	for _, test := range tests {
+		if len(test.neighborhood) != test.size {
+			t.Errorf("%s has %d offsets, want %d", test.name, len(test.neighborhood), test.size)
+		}
+
+		hasSelf := false
+		for k, offset := range test.neighborhood {
+			if offset == [2]int{0, 0} {
+				hasSelf = true
+			}
+			if k > 0 {
+				prev := test.neighborhood[k-1]
+				if prev[0] > offset[0] || (prev[0] == offset[0] && prev[1] >= offset[1]) {
+					t.Errorf("%s: offset %v follows %v, out of row-major order", test.name, offset, prev)
+				}
+			}
+		}
+		if !hasSelf {
+			t.Errorf("%s does not include the cell itself", test.name)
+		}
+	}
+
+	want := Neighborhood{{-1, 0}, {0, -1}, {0, 0}, {0, 1}, {1, 0}}
+	for k, offset := range VonNeumann(1) {
+		if offset != want[k] {
+			t.Errorf("VonNeumann(1)[%d] = %v, want %v", k, offset, want[k])
+		}
+	}
+
+	want = Neighborhood{{-1, -1}, {-1, 0}, {0, -1}, {0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	for k, offset := range Hexagonal() {
+		if offset != want[k] {
+			t.Errorf("Hexagonal()[%d] = %v, want %v", k, offset, want[k])
+		}
+	}
+}
+
+// TestParseNeighborhood tests that ParseNeighborhood sets the neighborhood and the self-interaction options it is given,
+// keeps the others from the Rules, and rejects unknown neighborhoods, options and radii.
+func TestParseNeighborhood(t *testing.T) {
+	tests := []struct {
+		s             string
+		r             int
+		size          int
+		selfPlay      bool
+		selfImitation bool
+	}{
+		{"moore", 1, 9, false, true},
+		{"moore:selfplay", 2, 25, true, true},
+		{"vonneumann:noselfimitation", 1, 5, false, false},
+		{"hexagonal:selfplay,noselfimitation", 3, 7, true, false},
+		{"moore:noselfplay,selfimitation", 1, 9, false, true},
+	}
+
+	for _, test := range tests {
+		rules, err := ParseNeighborhood(test.s, test.r, DefaultRules())
+		if err != nil {
+			t.Errorf("ParseNeighborhood(%q) returned %v", test.s, err)
+			continue
+		}
+		if len(rules.neighborhood) != test.size || rules.selfPlay != test.selfPlay || rules.selfImitation != test.selfImitation {
+			t.Errorf("ParseNeighborhood(%q, %d) gave %d offsets, self-play %v and self-imitation %v; want %d, %v and %v",
+				test.s, test.r, len(rules.neighborhood), rules.selfPlay, rules.selfImitation, test.size, test.selfPlay, test.selfImitation)
+		}
+		if rules.update != BestNeighbor || rules.boundary != Fixed {
+			t.Errorf("ParseNeighborhood(%q) changed rules other than the neighborhood and self-interaction", test.s)
+		}
+	}
+
+	for _, s := range []string{"square", "moore:selfish", "moore:", "vonneumann:selfplay,"} {
+		if _, err := ParseNeighborhood(s, 1, DefaultRules()); err == nil {
+			t.Errorf("ParseNeighborhood(%q) returned no error", s)
+		}
+	}
+	if _, err := ParseNeighborhood("moore", 0, DefaultRules()); err == nil {
+		t.Errorf("ParseNeighborhood with radius 0 returned no error")
+	}
+}
+
+// TestApplyBoundary tests every boundary on and just off both edges of a dimension of length 5, and one full length
+// and a half past them.
+func TestApplyBoundary(t *testing.T) {
+	const n = 5
+	tests := []struct {
+		i          int
+		fixed      int // -1 for missing
+		toroidal   int
+		reflecting int
+	}{
+		{0, 0, 0, 0},
+		{4, 4, 4, 4},
+		{-1, -1, 4, 0},
+		{n, -1, 0, 4},
+		{-n, -1, 0, 4},
+		{-n - 1, -1, 4, 4},
+		{n + 2, -1, 2, 2},
+		{2 * n, -1, 0, 0},
+		{2*n + 1, -1, 1, 1},
+	}
+
+	for _, test := range tests {
+		got, ok := ApplyBoundary(test.i, n, Fixed)
+		if (test.fixed == -1 && ok) || (test.fixed != -1 && (!ok || got != test.fixed)) {
+			t.Errorf("fixed boundary: ApplyBoundary(%d, %d) = %d, %v; want %d", test.i, n, got, ok, test.fixed)
+		}
+		if got, ok := ApplyBoundary(test.i, n, Toroidal); !ok || got != test.toroidal {
+			t.Errorf("toroidal boundary: ApplyBoundary(%d, %d) = %d, %v; want %d", test.i, n, got, ok, test.toroidal)
+		}
+		if got, ok := ApplyBoundary(test.i, n, Reflecting); !ok || got != test.reflecting {
+			t.Errorf("reflecting boundary: ApplyBoundary(%d, %d) = %d, %v; want %d", test.i, n, got, ok, test.reflecting)
+		}
+	}
+
+	// at a corner, a Moore neighbor is missing under a fixed boundary if either coordinate is off the board
+	rules := DefaultRules()
+	if _, _, ok := rules.Neighbor(0, 2, [2]int{-1, 0}, n, n); ok {
+		t.Errorf("fixed boundary found a neighbor above the top row")
+	}
+	rules.boundary = Toroidal
+	if k, l, ok := rules.Neighbor(0, 0, [2]int{-1, -1}, n, n); !ok || k != n-1 || l != n-1 {
+		t.Errorf("toroidal neighbor up and left of (0, 0) is (%d, %d), want (%d, %d)", k, l, n-1, n-1)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"stencil"
+)
+
+// EvolveParallel is a GameBoard method that takes a number of steps, a Game, the Rules, and a number of processors.
+// It evolves the board for steps generations in the same way as Evolve, but divides the rows of the board
+// over numProcs goroutines and reuses the same two boards for every generation.
+// Asynchronous updating is sequential by nature, so in that case the board is evolved serially.
+func (initialBoard GameBoard) EvolveParallel(steps int, game Game, rules Rules, numProcs int) []GameBoard {
+	if rules.asynchronous {
+		return initialBoard.Evolve(steps, game, rules)
+	}
+
+	boards := make([]GameBoard, steps+1)
+	boards[0] = initialBoard
+
+	// the rules apply their own boundary, so the engine only ever reads cells on the board
+	e := stencil.NewEngine(initialBoard, stencil.Fixed, numProcs)
+
+	for i := 1; i <= steps; i++ {
+		// first compute every score from the current strategies, then every strategy from those scores
+		e.Step(ScoreRule(game, rules))
+		e.Step(StrategyRule(game, rules, i))
+		boards[i] = e.Snapshot()
+	}
+
+	return boards
+}
+
+// ScoreRule takes a Game and the Rules and returns a stencil rule that keeps the strategy of a cell
+// and sets its score to the sum of its winnings against its neighbors, as in UpdateScores.
+func ScoreRule(game Game, rules Rules) stencil.Rule[Cell] {
+	return func(src *stencil.Reader[Cell], row, col int) Cell {
+		cellAt := func(k, l int) Cell {
+			c, _ := src.At(k, l)
+			return c
+		}
+
+		c := cellAt(row, col)
+		c.score = rules.CellScore(cellAt, row, col, src.Rows(), src.Cols(), game)
+
+		return c
+	}
+}
+
+// StrategyRule takes a Game, the Rules, and the number of the generation, and returns a stencil rule that
+// keeps the score of a cell and sets its strategy as in UpdateStrategies.
+func StrategyRule(game Game, rules Rules, generation int) stencil.Rule[Cell] {
+	return func(src *stencil.Reader[Cell], row, col int) Cell {
+		cellAt := func(k, l int) Cell {
+			c, _ := src.At(k, l)
+			return c
+		}
+
+		c := cellAt(row, col)
+		r := Stream{seed: rules.seed, generation: generation, index: row*src.Cols() + col}
+		c.strategy = rules.ChooseStrategy(cellAt, row, col, src.Rows(), src.Cols(), game, r)
+
+		return c
+	}
+}
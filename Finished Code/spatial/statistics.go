@@ -0,0 +1,89 @@
+package main
+
+// GenerationStats holds summary statistics of the board at one generation.
+type GenerationStats struct {
+	generation int
+	fractions  []float64 // fractions[s] is the fraction of cells playing strategy s
+	meanScores []float64 // meanScores[s] is the mean score of the cells playing strategy s, or 0 if there are none
+	changes    int       // the number of cells whose strategy changed since the previous generation
+}
+
+// ComputeStats takes the boards of a simulation along with the Game and Rules it was run with.
+// It returns the statistics of every generation. The scores are recomputed from the strategies of each board,
+// so that they are the scores each strategy earns at that generation.
+func ComputeStats(boards []GameBoard, game Game, rules Rules) []GenerationStats {
+	stats := make([]GenerationStats, len(boards))
+
+	for t := range boards {
+		stats[t] = BoardStats(boards[t], game, rules)
+		stats[t].generation = t
+		if t > 0 {
+			stats[t].changes = CountChanges(boards[t-1], boards[t])
+		}
+	}
+
+	return stats
+}
+
+// BoardStats takes a GameBoard, a Game, and the Rules, and returns the fraction and mean score of each strategy.
+func BoardStats(g GameBoard, game Game, rules Rules) GenerationStats {
+	var stats GenerationStats
+	n := game.NumStrategies()
+	stats.fractions = make([]float64, n)
+	stats.meanScores = make([]float64, n)
+
+	rows := len(g)
+	columns := len(g[0])
+	counts := make([]int, n)
+
+	cellAt := func(k, l int) Cell {
+		return g[k][l]
+	}
+
+	for i := range g {
+		for j := range g[i] {
+			s := g[i][j].strategy
+			counts[s]++
+			stats.meanScores[s] += rules.CellScore(cellAt, i, j, rows, columns, game)
+		}
+	}
+
+	for s := range counts {
+		stats.fractions[s] = float64(counts[s]) / float64(rows*columns)
+		if counts[s] > 0 {
+			stats.meanScores[s] /= float64(counts[s])
+		}
+	}
+
+	return stats
+}
+
+// CountChanges takes two GameBoards of the same dimensions and returns the number of cells whose strategies differ.
+func CountChanges(g1, g2 GameBoard) int {
+	count := 0
+	for i := range g1 {
+		for j := range g1[i] {
+			if g1[i][j].strategy != g2[i][j].strategy {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// SteadyStateFraction takes the statistics of a simulation, a strategy, and a fraction of the generations.
+// It returns the mean fraction of cells playing that strategy over the last window of the generations,
+// which estimates its level once the board has settled down. At least one generation is always used.
+func SteadyStateFraction(stats []GenerationStats, strategy int, window float64) float64 {
+	numGens := int(window * float64(len(stats)))
+	if numGens < 1 {
+		numGens = 1
+	}
+
+	total := 0.0
+	for _, s := range stats[len(stats)-numGens:] {
+		total += s.fractions[strategy]
+	}
+
+	return total / float64(numGens)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestBoardStats tests the fractions and mean scores of a 5 x 5 board of cooperators with a single defector in the
+// center, whose scores are those of TestUpdatePrisonersDilemma.
+func TestBoardStats(t *testing.T) {
+	g := SingleDefectorBoard(5, 5)
+	stats := BoardStats(g, PrisonersDilemma(1.5), DefaultRules())
+
+	// the cooperators' scores sum to 140 over the whole board, less the defector's 12
+	wantFractions := []float64{24.0 / 25.0, 1.0 / 25.0}
+	wantScores := []float64{128.0 / 24.0, 12}
+	for s := range wantFractions {
+		if math.Abs(stats.fractions[s]-wantFractions[s]) > 1e-12 || math.Abs(stats.meanScores[s]-wantScores[s]) > 1e-12 {
+			t.Errorf("strategy %d: fraction %g and mean score %g, want %g and %g",
+				s, stats.fractions[s], stats.meanScores[s], wantFractions[s], wantScores[s])
+		}
+	}
+
+	// one generation later the defectors fill the center 3 x 3, so 8 cells have changed
+	boards := g.Evolve(1, PrisonersDilemma(1.5), DefaultRules())
+	all := ComputeStats(boards, PrisonersDilemma(1.5), DefaultRules())
+	if all[1].generation != 1 || all[1].changes != 8 || math.Abs(all[1].fractions[1]-9.0/25.0) > 1e-12 {
+		t.Errorf("after one generation: %+v, want 8 changes and 9/25 defectors", all[1])
+	}
+}
+
+// TestSteadyStateFraction tests that SteadyStateFraction averages over the last window of generations, and uses at
+// least one generation.
+func TestSteadyStateFraction(t *testing.T) {
+	stats := make([]GenerationStats, 4)
+	for i, f := range []float64{1.0, 0.8, 0.6, 0.4} {
+		stats[i].fractions = []float64{f, 1 - f}
+	}
+
+	tests := []struct {
+		strategy int
+		window   float64
+		want     float64
+	}{
+		{0, 0.5, 0.5},
+		{0, 1, 0.7},
+		{0, 0, 0.4},
+		{1, 0.5, 0.5},
+		{1, 0.25, 0.6},
+	}
+
+	for _, test := range tests {
+		if got := SteadyStateFraction(stats, test.strategy, test.window); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("SteadyStateFraction of strategy %d over window %g = %g, want %g", test.strategy, test.window, got, test.want)
+		}
+	}
+}
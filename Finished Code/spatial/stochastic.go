@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"rng"
+)
+
+// UpdateRule says how a cell chooses its new strategy from the scores in its neighborhood.
+type UpdateRule int
+
+const (
+	BestNeighbor UpdateRule = iota // copy the strategy of the highest scoring cell in the neighborhood
+	Fermi                          // pick a random neighbor and copy it with probability 1 / (1 + exp((own - neighbor) / K))
+	Proportional                   // pick a random neighbor and copy it with probability proportional to how much better it scored
+)
+
+// UpdateRuleByName takes the name of an update rule ("best", "fermi", or "proportional") and returns that UpdateRule.
+func UpdateRuleByName(name string) (UpdateRule, error) {
+	switch name {
+	case "best":
+		return BestNeighbor, nil
+	case "fermi":
+		return Fermi, nil
+	case "proportional":
+		return Proportional, nil
+	}
+
+	return BestNeighbor, fmt.Errorf("unknown update rule %s", name)
+}
+
+// Stream is a source of reproducible random numbers for one cell in one generation.
+// Rather than drawing from a shared generator, whose results would depend on the order in which cells are
+// visited, each number is a hash of the seed, the generation, the index of the cell, and the number of the draw.
+// This way the serial and parallel versions of the game make exactly the same random choices.
+// Each of these is mixed in with rng.DeriveSeed, which hashes what came before it first, so that nearby seeds
+// don't replay each other's numbers at shifted generations.
+type Stream struct {
+	seed       int64
+	generation int
+	index      int
+}
+
+// Float64 is a Stream method that takes the number of a draw and returns a uniform random number in [0, 1).
+func (s Stream) Float64(draw int) float64 {
+	x := rng.DeriveSeed(s.seed, s.generation)
+	x = rng.DeriveSeed(x, s.index)
+	x = rng.DeriveSeed(x, draw)
+	return float64(uint64(x)>>11) / (1 << 53)
+}
+
+// Intn is a Stream method that takes the number of a draw and a positive integer n, and returns a uniform
+// random integer in [0, n).
+func (s Stream) Intn(draw, n int) int {
+	return int(s.Float64(draw) * float64(n))
+}
+
+// PayoffRange is a Game method that returns the difference between its largest and smallest payoffs.
+func (game Game) PayoffRange() float64 {
+	min, max := math.Inf(1), math.Inf(-1)
+	for i := range game.payoffs {
+		for _, p := range game.payoffs[i] {
+			min = math.Min(min, p)
+			max = math.Max(max, p)
+		}
+	}
+	return max - min
+}
+
+// NumPartners is a Rules method that returns the number of games a cell plays each generation away from the edges.
+func (rules Rules) NumPartners() int {
+	if rules.selfPlay {
+		return len(rules.neighborhood)
+	}
+	return len(rules.neighborhood) - 1
+}
+
+// CellScore is a Rules method that takes a function giving the cell at each position, the position (i, j) of a cell,
+// the dimensions of the board, and a Game. It returns the sum of the cell's winnings against its neighbors.
+func (rules Rules) CellScore(cellAt func(k, l int) Cell, i, j, rows, columns int, game Game) float64 {
+	c := cellAt(i, j)
+	score := 0.0
+
+	for _, offset := range rules.neighborhood {
+		if offset == [2]int{0, 0} && !rules.selfPlay {
+			continue
+		}
+		if k, l, ok := rules.Neighbor(i, j, offset, rows, columns); ok {
+			score += GameBetween(c, cellAt(k, l), game)
+		}
+	}
+
+	return score
+}
+
+// ChooseStrategy is a Rules method that takes a function giving the cell at each position, with its current strategy
+// and score, along with the position (i, j) of a cell, the dimensions of the board, a Game, and a Stream.
+// It returns the new strategy of the cell under the update rule, followed by mutation.
+func (rules Rules) ChooseStrategy(cellAt func(k, l int) Cell, i, j, rows, columns int, game Game, r Stream) int {
+	self := cellAt(i, j)
+	strategy := self.strategy
+
+	if rules.update == BestNeighbor {
+		// ties go to the first maximum in row-major order
+		max := math.Inf(-1) // payoffs may be negative
+		for _, offset := range rules.neighborhood {
+			if offset == [2]int{0, 0} && !rules.selfImitation {
+				continue
+			}
+			if k, l, ok := rules.Neighbor(i, j, offset, rows, columns); ok {
+				if neighbor := cellAt(k, l); neighbor.score > max {
+					max = neighbor.score
+					strategy = neighbor.strategy
+				}
+			}
+		}
+	} else {
+		// pairwise imitation: compare with a single neighbor chosen uniformly at random
+		neighbors := make([][2]int, 0, len(rules.neighborhood))
+		for _, offset := range rules.neighborhood {
+			if offset == [2]int{0, 0} {
+				continue
+			}
+			if k, l, ok := rules.Neighbor(i, j, offset, rows, columns); ok {
+				neighbors = append(neighbors, [2]int{k, l})
+			}
+		}
+
+		if len(neighbors) > 0 {
+			chosen := neighbors[r.Intn(1, len(neighbors))]
+			neighbor := cellAt(chosen[0], chosen[1])
+			if r.Float64(2) < rules.ImitationProbability(self.score, neighbor.score, game) {
+				strategy = neighbor.strategy
+			}
+		}
+	}
+
+	if rules.mutationRate > 0 && r.Float64(3) < rules.mutationRate {
+		strategy = r.Intn(4, game.NumStrategies())
+	}
+
+	return strategy
+}
+
+// ImitationProbability is a Rules method that takes the score of a cell, the score of the neighbor it is compared with,
+// and a Game. It returns the probability that the cell copies the neighbor's strategy under pairwise imitation.
+func (rules Rules) ImitationProbability(ownScore, neighborScore float64, game Game) float64 {
+	if rules.update == Fermi {
+		// in the zero temperature limit, only strictly better neighbors are copied
+		if rules.temperature <= 0 {
+			if neighborScore > ownScore {
+				return 1.0
+			}
+			return 0.0
+		}
+		return 1.0 / (1.0 + math.Exp((ownScore-neighborScore)/rules.temperature))
+	}
+
+	// proportional imitation: normalize by the largest possible difference in scores
+	maxDifference := float64(rules.NumPartners()) * game.PayoffRange()
+	if maxDifference <= 0 || neighborScore <= ownScore {
+		return 0.0
+	}
+	return math.Min(1.0, (neighborScore-ownScore)/maxDifference)
+}
+
+// UpdateAsynchronous takes a GameBoard, a Game, the Rules, and the number of the generation.
+// It returns a new GameBoard after rows x columns random sequential updates, in each of which a uniformly chosen
+// cell recomputes the scores it needs from the current strategies and then chooses its new strategy right away,
+// so that later updates in the same generation see its change. The scores of the returned board are up to date.
+func UpdateAsynchronous(g1 GameBoard, game Game, rules Rules, generation int) GameBoard {
+	rows := len(g1)
+	columns := len(g1[0])
+
+	g2 := CreateBoard(rows, columns)
+	for i := range g2 {
+		copy(g2[i], g1[i])
+	}
+
+	strategyAt := func(k, l int) Cell {
+		return Cell{strategy: g2[k][l].strategy}
+	}
+	cellAt := func(k, l int) Cell {
+		return Cell{strategy: g2[k][l].strategy, score: rules.CellScore(strategyAt, k, l, rows, columns, game)}
+	}
+
+	for step := 0; step < rows*columns; step++ {
+		r := Stream{seed: rules.seed, generation: generation, index: step}
+		cell := r.Intn(0, rows*columns)
+		i, j := cell/columns, cell%columns
+		g2[i][j].strategy = rules.ChooseStrategy(cellAt, i, j, rows, columns, game, r)
+	}
+
+	// record the scores of the final strategies
+	for i := range g2 {
+		for j := range g2[i] {
+			g2[i][j].score = rules.CellScore(strategyAt, i, j, rows, columns, game)
+		}
+	}
+
+	return g2
+}
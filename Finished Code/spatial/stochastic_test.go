@@ -0,0 +1,164 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestStreamSeedsIndependent tests that the streams of two seeds one apart neither repeat each other's numbers
+// at shifted generations nor are correlated across generations.
+func TestStreamSeedsIndependent(t *testing.T) {
+	const numGens = 4096
+	var seed int64 = 2
+
+	first := make([]float64, numGens)
+	second := make([]float64, numGens)
+	seen := make(map[float64]int, numGens)
+	for g := range first {
+		first[g] = Stream{seed: seed, generation: g, index: 0}.Float64(0)
+		second[g] = Stream{seed: seed + 1, generation: g, index: 0}.Float64(0)
+		seen[first[g]] = g
+	}
+
+	for g, x := range second {
+		if h, ok := seen[x]; ok {
+			t.Fatalf("seed %d at generation %d draws the same number as seed %d at generation %d", seed+1, g, seed, h)
+		}
+	}
+
+	// with independent uniform draws, each correlation is about normal with standard deviation 1 / sqrt(n)
+	for lag := -3; lag <= 3; lag++ {
+		var xs, ys []float64
+		for g := range first {
+			if g+lag >= 0 && g+lag < numGens {
+				xs = append(xs, first[g])
+				ys = append(ys, second[g+lag])
+			}
+		}
+		if r := Correlation(xs, ys); math.Abs(r) > 4/math.Sqrt(float64(len(xs))) {
+			t.Errorf("seeds %d and %d are correlated at lag %d: r = %g", seed, seed+1, lag, r)
+		}
+	}
+}
+
+// Correlation takes two slices of the same length and returns their Pearson correlation.
+func Correlation(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i] / n
+		meanY += ys[i] / n
+	}
+
+	var cov, varX, varY float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		varX += (xs[i] - meanX) * (xs[i] - meanX)
+		varY += (ys[i] - meanY) * (ys[i] - meanY)
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// TestImitationProbabilityFermi tests that Fermi imitation copies a neighbor with an equal score half of the time,
+// and that as the temperature goes to 0 it copies better neighbors surely and worse neighbors never.
+func TestImitationProbabilityFermi(t *testing.T) {
+	rules := DefaultRules()
+	rules.update = Fermi
+	game := PrisonersDilemma(1.5)
+
+	for _, temperature := range []float64{0.01, 0.1, 1, 10} {
+		rules.temperature = temperature
+		if p := rules.ImitationProbability(3, 3, game); p != 0.5 {
+			t.Errorf("temperature %g: probability at equal scores = %g, want 0.5", temperature, p)
+		}
+	}
+
+	for _, temperature := range []float64{1e-3, 0} {
+		rules.temperature = temperature
+		if p := rules.ImitationProbability(3, 4, game); p < 0.999 {
+			t.Errorf("temperature %g: probability of copying a better neighbor = %g, want near 1", temperature, p)
+		}
+		if p := rules.ImitationProbability(4, 3, game); p > 0.001 {
+			t.Errorf("temperature %g: probability of copying a worse neighbor = %g, want near 0", temperature, p)
+		}
+	}
+}
+
+// TestImitationProbabilityProportional tests that proportional imitation gives a probability in [0, 1] for every pair
+// of scores, and never copies a neighbor that scored no better.
+func TestImitationProbabilityProportional(t *testing.T) {
+	rules := DefaultRules()
+	rules.update = Proportional
+	game := PrisonersDilemma(1.85)
+
+	for own := -2.0; own <= 20; own += 0.25 {
+		for neighbor := -2.0; neighbor <= 20; neighbor += 0.25 {
+			p := rules.ImitationProbability(own, neighbor, game)
+			if p < 0 || p > 1 {
+				t.Fatalf("probability for scores %g and %g = %g, outside [0, 1]", own, neighbor, p)
+			}
+			if neighbor <= own && p != 0 {
+				t.Fatalf("probability of copying a neighbor scoring %g over %g = %g, want 0", neighbor, own, p)
+			}
+		}
+	}
+}
+
+// TestChooseStrategyNoMutation tests that without mutation, a cell that scores at least as well as all of its
+// neighbors keeps its strategy under every update rule that only copies better neighbors, whatever the random numbers.
+func TestChooseStrategyNoMutation(t *testing.T) {
+	game := PrisonersDilemma(1.5)
+	cellAt := func(k, l int) Cell {
+		if k == 1 && l == 1 {
+			return Cell{strategy: 0, score: 5}
+		}
+		return Cell{strategy: 1, score: float64(k + l)} // at most 4
+	}
+
+	for _, update := range []UpdateRule{BestNeighbor, Proportional, Fermi} {
+		rules := DefaultRules()
+		rules.update = update
+		rules.temperature = 0
+		rules.mutationRate = 0
+
+		for generation := 0; generation < 500; generation++ {
+			r := Stream{seed: 3, generation: generation, index: 4}
+			if s := rules.ChooseStrategy(cellAt, 1, 1, 3, 3, game, r); s != 0 {
+				t.Fatalf("update rule %d, generation %d: cell with the best score changed to strategy %d", update, generation, s)
+			}
+		}
+	}
+}
+
+// TestUpdateAsynchronousSeed tests that asynchronous runs with the same seed are identical, and that a different
+// seed gives a different run.
+func TestUpdateAsynchronousSeed(t *testing.T) {
+	game := PrisonersDilemma(1.6)
+	rules := DefaultRules()
+	rules.asynchronous = true
+	rules.update = Fermi
+	rules.mutationRate = 0.01
+
+	initialBoard := RandomBoard(20, 20, 0.3, 5)
+	first := initialBoard.Evolve(10, game, rules)
+	second := initialBoard.Evolve(10, game, rules)
+	rules.seed++
+	other := initialBoard.Evolve(10, game, rules)
+
+	differs := false
+	for g := range first {
+		for i := range first[g] {
+			for j := range first[g][i] {
+				if first[g][i][j] != second[g][i][j] {
+					t.Fatalf("generation %d: cell (%d, %d) differs between runs with the same seed", g, i, j)
+				}
+				if first[g][i][j] != other[g][i][j] {
+					differs = true
+				}
+			}
+		}
+	}
+	if !differs {
+		t.Errorf("asynchronous runs with different seeds are identical")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"math"
+	"rng"
+	"workpool"
+)
+
+// SweepPoint holds the steady-state cooperation level of the Prisoner's Dilemma at one payoff b and initial defector density.
+type SweepPoint struct {
+	b, density  float64
+	cooperation float64 // the mean steady-state fraction of cooperators over the trials
+	stdDev      float64 // the standard deviation of the steady-state fraction of cooperators over the trials
+}
+
+// SteadyStateWindow is the fraction of the final generations over which the cooperator fraction is averaged.
+const SteadyStateWindow = 0.25
+
+// SweepB takes the size of a square board, a number of steps, slices of b values and initial defector densities,
+// a number of trials, the Rules, and a number of processors. For every pair of b and density, it evolves trials
+// random boards with the Prisoner's Dilemma and records the steady-state fraction of cooperators.
+// The pairs are divided over numProcs goroutines. Trial t uses the seed rng.DeriveSeed(rules.seed, t) for its board and its rules,
+// so the results do not depend on the number of processors.
+func SweepB(size, steps int, bValues, densities []float64, trials int, rules Rules, numProcs int) []SweepPoint {
+	points := make([]SweepPoint, 0, len(bValues)*len(densities))
+	for _, density := range densities {
+		for _, b := range bValues {
+			points = append(points, SweepPoint{b: b, density: density})
+		}
+	}
+
+	// each processor takes about the same number of points, and the last one takes any remainder
+	workpool.For(len(points), numProcs, func(worker, start, end int) {
+		SweepOneProc(points[start:end], size, steps, trials, rules)
+	})
+
+	return points
+}
+
+// SweepOneProc takes a slice of SweepPoints, the size of a square board, a number of steps, a number of trials,
+// and the Rules. It fills in the cooperation level of every point.
+func SweepOneProc(points []SweepPoint, size, steps, trials int, rules Rules) {
+	for i := range points {
+		game := PrisonersDilemma(points[i].b)
+		fractions := make([]float64, trials)
+
+		for t := range fractions {
+			trialRules := rules
+			trialRules.seed = rng.DeriveSeed(rules.seed, t)
+
+			initialBoard := RandomBoard(size, size, points[i].density, trialRules.seed)
+			boards := initialBoard.Evolve(steps, game, trialRules)
+			fractions[t] = SteadyStateFraction(ComputeStats(boards, game, trialRules), 0, SteadyStateWindow)
+		}
+
+		points[i].cooperation, points[i].stdDev = MeanStdDev(fractions)
+	}
+}
+
+// MeanStdDev takes a slice of decimals and returns their mean and (population) standard deviation.
+func MeanStdDev(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+
+	return mean, math.Sqrt(variance)
+}
+
+// LinearRange takes a minimum, a maximum, and a number of values n, and returns n evenly spaced values
+// from min to max inclusive. If n is 1, it returns just min.
+func LinearRange(min, max float64, n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		if n == 1 {
+			values[i] = min
+		} else {
+			values[i] = min + (max-min)*float64(i)/float64(n-1)
+		}
+	}
+	return values
+}
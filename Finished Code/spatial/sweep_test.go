@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestSweepBProcessors tests that SweepB gives identical points for 1 and 4 processors with the same seed, under
+// a stochastic update rule whose every choice depends on the seed.
+func TestSweepBProcessors(t *testing.T) {
+	rules := DefaultRules()
+	rules.update = Fermi
+	rules.mutationRate = 0.01
+	rules.seed = 11
+
+	bValues := []float64{1.2, 1.6, 1.9}
+	densities := []float64{0.1, 0.3}
+
+	serial := SweepB(10, 12, bValues, densities, 3, rules, 1)
+	parallel := SweepB(10, 12, bValues, densities, 3, rules, 4)
+
+	if len(serial) != len(bValues)*len(densities) || len(parallel) != len(serial) {
+		t.Fatalf("got %d and %d points, want %d", len(serial), len(parallel), len(bValues)*len(densities))
+	}
+	for i := range serial {
+		if serial[i] != parallel[i] {
+			t.Errorf("point %d: %+v with 1 processor and %+v with 4", i, serial[i], parallel[i])
+		}
+		if serial[i].cooperation < 0 || serial[i].cooperation > 1 {
+			t.Errorf("point %d: cooperation %g outside [0, 1]", i, serial[i].cooperation)
+		}
+	}
+}
@@ -20,21 +20,22 @@ func InitializeUniverse(galaxies []Galaxy, w float64) *Universe {
 }
 
 // InitializeGalaxy takes number of stars in the galaxy, radius of the galaxy to be constructed,
-// and center of galaxy to be constructed. Returns a spinning Galaxy object -- which is just a slice of Star pointers
-func InitializeGalaxy(numOfStars int, r, x, y float64) Galaxy {
+// center of galaxy to be constructed, and a random stream to place the stars with.
+// Returns a spinning Galaxy object -- which is just a slice of Star pointers
+func InitializeGalaxy(numOfStars int, r, x, y float64, source *rand.Rand) Galaxy {
 	g := make(Galaxy, numOfStars)
 
 	for i := range g {
 		var s Star
 
 		// First choose distance to center of galaxy
-		dist := (rand.Float64() + 1.0) / 2.0
+		dist := (source.Float64() + 1.0) / 2.0
 
 		// multiply by factor of r
 		dist *= r
 
 		// Next choose the angle in radians to represent the rotation
-		angle := rand.Float64() * 2 * math.Pi
+		angle := source.Float64() * 2 * math.Pi
 
 		// convert polar coordinates to Cartesian
 		s.position.x = x + dist*math.Cos(angle)
@@ -3,16 +3,32 @@ package main
 import (
 	"fmt"
 	"gifhelper"
+	"os"
+	"rng"
 )
 
 func main() {
 
+	// an optional seed on the command line repeats an earlier run; otherwise the seed comes from the clock
+	seedString := "time"
+	if len(os.Args) > 1 {
+		seedString = os.Args[1]
+	}
+	seed, err := rng.ParseSeed(seedString)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Seed:", seed)
+
+	// each galaxy gets its own stream derived from the seed
+	streams := rng.Streams(seed, 2)
+
 	// the following sample parameters may be helpful for the "collide" command
 	// all units are in SI (meters, kg, etc.)
 	// but feel free to change the positions of the galaxies.
 
-	g0 := InitializeGalaxy(500, 4e21, 7e22, 2e22)
-	g1 := InitializeGalaxy(500, 4e21, 3e22, 7e22)
+	g0 := InitializeGalaxy(500, 4e21, 7e22, 2e22, streams[0])
+	g1 := InitializeGalaxy(500, 4e21, 3e22, 7e22, streams[1])
 
 	// you probably want to apply a "push" function at this point to these galaxies to move
 	// them toward each other to collide.
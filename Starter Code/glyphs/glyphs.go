@@ -0,0 +1,71 @@
+// Package glyphs provides a tiny 3 x 5 bitmap font for labelling images that are drawn pixel by pixel,
+// such as the axes of a plot or the rows and columns of a mosaic, without depending on a font library.
+package glyphs
+
+// Font holds a 3 x 5 bitmap for every character that can be drawn. Each string is one row of the glyph,
+// where '#' marks a filled pixel.
+var Font = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'.': {"...", "...", "...", "...", ".#."},
+	'-': {"...", "...", "###", "...", "..."},
+	'=': {"...", "###", "...", "###", "..."},
+	'a': {"...", ".##", "#.#", "#.#", ".##"},
+	'b': {"#..", "#..", "###", "#.#", "###"},
+	'c': {"...", "###", "#..", "#..", "###"},
+	'd': {"..#", "..#", "###", "#.#", "###"},
+	'e': {"###", "#.#", "###", "#..", "###"},
+	'f': {".##", "#..", "##.", "#..", "#.."},
+	'i': {".#.", "...", ".#.", ".#.", ".#."},
+	'k': {"#..", "#.#", "##.", "#.#", "#.#"},
+	'n': {"...", "##.", "#.#", "#.#", "#.#"},
+	'o': {"...", "###", "#.#", "#.#", "###"},
+	'p': {"###", "#.#", "###", "#..", "#.."},
+	'r': {"...", "###", "#..", "#..", "#.."},
+	's': {".##", "#..", ".#.", "..#", "##."},
+	't': {".#.", "###", ".#.", ".#.", ".##"},
+	'y': {"#.#", "#.#", "###", "..#", "###"},
+}
+
+// Draw takes a label, the position (x, y) of its top left corner, a scale, and a function that fills the rectangle
+// from (x1, y1) up to but not including (x2, y2). It draws the label by filling a scale x scale square for every
+// pixel of its glyphs. Characters without a glyph are left blank.
+func Draw(label string, x, y, scale int, fill func(x1, y1, x2, y2 int)) {
+	for _, ch := range label {
+		if glyph, ok := Font[ch]; ok {
+			for r, line := range glyph {
+				for k, pixel := range line {
+					if pixel == '#' {
+						px := x + k*scale
+						py := y + r*scale
+						fill(px, py, px+scale, py+scale)
+					}
+				}
+			}
+		}
+		// advance by the glyph width plus one column of space
+		x += 4 * scale
+	}
+}
+
+// Width takes a label and a scale and returns the width in pixels of drawing it with Draw.
+func Width(label string, scale int) int {
+	n := len([]rune(label))
+	if n == 0 {
+		return 0
+	}
+	return (4*n - 1) * scale
+}
+
+// Height takes a scale and returns the height in pixels of a label drawn with Draw.
+func Height(scale int) int {
+	return 5 * scale
+}
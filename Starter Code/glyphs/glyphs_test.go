@@ -0,0 +1,49 @@
+package glyphs
+
+import (
+	"testing"
+)
+
+// TestFont checks that every glyph is 3 pixels wide and made only of '#' and '.'.
+func TestFont(t *testing.T) {
+	for ch, glyph := range Font {
+		for r, line := range glyph {
+			if len(line) != 3 {
+				t.Errorf("row %d of %q is %d pixels wide, want 3", r, ch, len(line))
+			}
+			for _, pixel := range line {
+				if pixel != '#' && pixel != '.' {
+					t.Errorf("row %d of %q holds %q", r, ch, pixel)
+				}
+			}
+		}
+	}
+}
+
+// TestDraw checks that Draw fills one square per pixel of the glyphs, inside the width and height of the label,
+// and skips characters without a glyph.
+func TestDraw(t *testing.T) {
+	label := "1.5 k"
+	scale := 3
+	x, y := 10, 20
+
+	numFilled := 0
+	Draw(label, x, y, scale, func(x1, y1, x2, y2 int) {
+		numFilled++
+		if x2-x1 != scale || y2-y1 != scale {
+			t.Errorf("filled a %d x %d square, want %d x %d", x2-x1, y2-y1, scale, scale)
+		}
+		if x1 < x || x2 > x+Width(label, scale) || y1 < y || y2 > y+Height(scale) {
+			t.Errorf("filled (%d, %d) to (%d, %d), outside the label", x1, y1, x2, y2)
+		}
+	})
+
+	// 1 has 8 pixels, . has 1, 5 has 11, space has none, and k has 9
+	if numFilled != 29 {
+		t.Errorf("filled %d squares, want 29", numFilled)
+	}
+
+	if Width("", scale) != 0 || Width("10", 2) != 14 {
+		t.Errorf("Width(\"\") = %d and Width(\"10\", 2) = %d, want 0 and 14", Width("", scale), Width("10", 2))
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"math"
+	"stencil"
+	"workpool"
+)
+
+// AtlasEntry holds the result of a single simulation in a parameter sweep:
+// the parameters used, the final board, its summary statistics, and the pattern regime it falls into.
+// Unlike the killRate of SimulateGrayScott, which is the total rate at which predator is removed,
+// pearsonK follows Pearson's convention, so that predator is removed at rate feedRate + pearsonK.
+type AtlasEntry struct {
+	feedRate, pearsonK float64
+	finalBoard         Board
+	stats              PatternStats
+	regime             string
+}
+
+// PatternStats summarizes the predator concentration of a board.
+type PatternStats struct {
+	mean, stdDev float64 // mean and standard deviation of predator concentration
+	spotCount    int     // number of connected regions of high predator concentration
+	anisotropy   float64 // mean elongation of those regions, from 0 (round) to 1 (a line)
+	coverage     float64 // fraction of cells belonging to those regions
+}
+
+// SweepParameters takes an initial Board, a number of generations, a slice of feed rates, a slice of values of
+// Pearson's k, diffusion rates for prey and predator, a Laplacian kernel, and a number of processors.
+// Predator is removed at rate feedRate + pearsonK, as in Pearson's classification of Gray-Scott patterns.
+// It runs one simulation with periodic boundaries for every (feed rate, k) pair, dividing the simulations
+// over numProcs goroutines, and returns a grid of AtlasEntry objects in which rows correspond to feed rates and
+// columns correspond to values of k.
+func SweepParameters(initialBoard Board, numGens int, feedRates, pearsonKs []float64, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64, numProcs int) [][]AtlasEntry {
+	atlas := make([][]AtlasEntry, len(feedRates))
+	for i := range atlas {
+		atlas[i] = make([]AtlasEntry, len(pearsonKs))
+		for j := range atlas[i] {
+			atlas[i][j].feedRate = feedRates[i]
+			atlas[i][j].pearsonK = pearsonKs[j]
+		}
+	}
+
+	// number the simulations 0, 1, ..., numSims - 1 and hand out blocks of them to each worker
+	numSims := len(feedRates) * len(pearsonKs)
+
+	workpool.For(numSims, numProcs, func(worker, startIndex, endIndex int) {
+		SweepOneProc(atlas, startIndex, endIndex, initialBoard, numGens, preyDiffusionRate, predatorDiffusionRate, kernel)
+	})
+
+	return atlas
+}
+
+// SweepOneProc runs the simulations numbered startIndex up to but not including endIndex in atlas,
+// filling in each entry's final board, statistics, and regime.
+func SweepOneProc(atlas [][]AtlasEntry, startIndex, endIndex int, initialBoard Board, numGens int, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64) {
+	numCols := len(atlas[0])
+
+	for n := startIndex; n < endIndex; n++ {
+		entry := &atlas[n/numCols][n%numCols]
+
+		// each simulation runs on a single goroutine since the simulations themselves are run in parallel
+		e := stencil.NewEngine(initialBoard, stencil.Periodic, 1)
+		e.Run(numGens, GrayScottRule(entry.feedRate, entry.feedRate+entry.pearsonK, preyDiffusionRate, predatorDiffusionRate, kernel))
+
+		entry.finalBoard = e.Snapshot()
+		entry.stats = ComputePatternStats(entry.finalBoard)
+		entry.regime = ClassifyPattern(entry.stats)
+	}
+}
+
+// ComputePatternStats takes a Board and returns a PatternStats object summarizing its predator concentration.
+// Regions of high concentration are the connected components of cells whose predator concentration
+// lies above the midpoint between the minimum and maximum concentration on the board.
+func ComputePatternStats(b Board) PatternStats {
+	var stats PatternStats
+
+	numCells := float64(CountRows(b) * CountCols(b))
+	minVal, maxVal := math.Inf(1), math.Inf(-1)
+
+	for r := range b {
+		for c := range b[r] {
+			val := b[r][c][1]
+			stats.mean += val
+			minVal = math.Min(minVal, val)
+			maxVal = math.Max(maxVal, val)
+		}
+	}
+	stats.mean /= numCells
+
+	for r := range b {
+		for c := range b[r] {
+			diff := b[r][c][1] - stats.mean
+			stats.stdDev += diff * diff
+		}
+	}
+	stats.stdDev = math.Sqrt(stats.stdDev / numCells)
+
+	// a flat board has no regions to speak of
+	if maxVal-minVal < 1e-6 {
+		return stats
+	}
+
+	regions := FindRegions(b, (minVal+maxVal)/2)
+	stats.spotCount = len(regions)
+
+	totalSize := 0
+	for _, region := range regions {
+		totalSize += len(region)
+		stats.anisotropy += float64(len(region)) * Elongation(region)
+	}
+	if totalSize > 0 {
+		stats.anisotropy /= float64(totalSize)
+	}
+	stats.coverage = float64(totalSize) / numCells
+
+	return stats
+}
+
+// FindRegions takes a Board and a threshold.
+// It returns the 4-connected regions of cells whose predator concentration exceeds threshold,
+// where each region is a slice of [row, col] coordinates. The board is periodic, as in SweepParameters,
+// so a region may wrap around an edge; its coordinates are then continued past the edge rather than
+// wrapped, so that a spot split by the edge keeps its shape.
+func FindRegions(b Board, threshold float64) [][][2]int {
+	numRows := CountRows(b)
+	numCols := CountCols(b)
+
+	visited := make([][]bool, numRows)
+	for r := range visited {
+		visited[r] = make([]bool, numCols)
+	}
+
+	regions := make([][][2]int, 0)
+
+	for r := range b {
+		for c := range b[r] {
+			if visited[r][c] || b[r][c][1] <= threshold {
+				continue
+			}
+
+			// flood fill from (r, c), using region itself as the queue of cells to expand
+			region := [][2]int{{r, c}}
+			visited[r][c] = true
+
+			for k := 0; k < len(region); k++ {
+				row, col := region[k][0], region[k][1]
+				for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+					nr, nc := row+d[0], col+d[1]
+					wr, wc := stencil.Wrap(nr, numRows), stencil.Wrap(nc, numCols)
+					if !visited[wr][wc] && b[wr][wc][1] > threshold {
+						visited[wr][wc] = true
+						region = append(region, [2]int{nr, nc})
+					}
+				}
+			}
+
+			regions = append(regions, region)
+		}
+	}
+
+	return regions
+}
+
+// Elongation takes a region of [row, col] coordinates and returns 1 - (minor / major), where minor and major
+// are the eigenvalues of the covariance matrix of the coordinates. Round regions give values near 0 and
+// thin stripes give values near 1.
+func Elongation(region [][2]int) float64 {
+	if len(region) < 2 {
+		return 0.0
+	}
+
+	n := float64(len(region))
+	var meanRow, meanCol float64
+	for _, cell := range region {
+		meanRow += float64(cell[0])
+		meanCol += float64(cell[1])
+	}
+	meanRow /= n
+	meanCol /= n
+
+	var varRow, varCol, cov float64
+	for _, cell := range region {
+		dr := float64(cell[0]) - meanRow
+		dc := float64(cell[1]) - meanCol
+		varRow += dr * dr
+		varCol += dc * dc
+		cov += dr * dc
+	}
+	varRow /= n
+	varCol /= n
+	cov /= n
+
+	// eigenvalues of the symmetric 2 x 2 matrix [[varRow, cov], [cov, varCol]]
+	halfTrace := (varRow + varCol) / 2
+	root := math.Sqrt(math.Max(0, halfTrace*halfTrace-(varRow*varCol-cov*cov)))
+	major := halfTrace + root
+	minor := halfTrace - root
+
+	if major <= 0 {
+		return 0.0
+	}
+
+	return 1 - minor/major
+}
+
+// ClassifyPattern takes a PatternStats object and returns the name of the pattern regime it belongs to:
+// "empty" if predators have died out, "uniform" if the board has no structure, "stripes" if the regions of
+// high concentration are long or merge into a labyrinth, "spots" if they are many small round regions, and
+// "mixed" otherwise.
+func ClassifyPattern(stats PatternStats) string {
+	if stats.stdDev < 0.01 {
+		if stats.mean < 0.01 {
+			return "empty"
+		}
+		return "uniform"
+	}
+
+	if stats.anisotropy > 0.6 || (stats.spotCount > 0 && stats.coverage/float64(stats.spotCount) > 0.05) {
+		return "stripes"
+	}
+
+	if stats.spotCount >= 5 {
+		return "spots"
+	}
+
+	return "mixed"
+}
+
+// LinearRange takes a minimum, a maximum, and a number of values n.
+// It returns n evenly spaced values from minVal to maxVal inclusive.
+func LinearRange(minVal, maxVal float64, n int) []float64 {
+	values := make([]float64, n)
+
+	if n == 1 {
+		values[0] = minVal
+		return values
+	}
+
+	for i := range values {
+		values[i] = minVal + float64(i)*(maxVal-minVal)/float64(n-1)
+	}
+
+	return values
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestFindRegionsWrap tests that a spot split across the corners of the periodic board is found as a single round
+// region, separate from a spot in the middle of the board.
+func TestFindRegionsWrap(t *testing.T) {
+	b := InitializeBoard(20, 20)
+	// a 4 x 4 spot whose top left corner is two cells from the bottom right corner of the board
+	for r := 18; r < 22; r++ {
+		for c := 18; c < 22; c++ {
+			b[r%20][c%20][1] = 1.0
+		}
+	}
+	AddPredatorSquare(b, 8, 8, 3)
+
+	regions := FindRegions(b, 0.5)
+	if len(regions) != 2 {
+		t.Fatalf("found %d regions, want 2", len(regions))
+	}
+
+	sizes := map[int]bool{len(regions[0]): true, len(regions[1]): true}
+	if !sizes[16] || !sizes[9] {
+		t.Fatalf("found regions of %d and %d cells, want 16 and 9", len(regions[0]), len(regions[1]))
+	}
+
+	for _, region := range regions {
+		if e := Elongation(region); e > 0.01 {
+			t.Errorf("square region of %d cells has elongation %g, want 0", len(region), e)
+		}
+	}
+}
+
+// TestElongationStripe tests that stripes have elongation near 1 and squares have elongation 0.
+func TestElongationStripe(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows, cols int
+		minE, maxE float64
+	}{
+		{"line", 1, 15, 1, 1},
+		{"stripe", 2, 30, 0.99, 1},
+		{"square", 5, 5, 0, 1e-12},
+		{"single cell", 1, 1, 0, 0},
+	}
+
+	for _, test := range tests {
+		region := make([][2]int, 0)
+		for r := 0; r < test.rows; r++ {
+			for c := 0; c < test.cols; c++ {
+				region = append(region, [2]int{r, c})
+			}
+		}
+
+		if e := Elongation(region); e < test.minE || e > test.maxE {
+			t.Errorf("%s: elongation = %g, want between %g and %g", test.name, e, test.minE, test.maxE)
+		}
+	}
+}
+
+// TestClassifyPattern tests the regimes of boards with no predators, a flat concentration of predators,
+// a stripe, and many small spots.
+func TestClassifyPattern(t *testing.T) {
+	empty := InitializeBoard(30, 30)
+
+	flat := InitializeBoard(30, 30)
+	for r := range flat {
+		for c := range flat[r] {
+			flat[r][c][1] = 0.4
+		}
+	}
+
+	stripe := InitializeBoard(30, 30)
+	for c := range stripe[10] {
+		stripe[10][c][1] = 1.0
+		stripe[11][c][1] = 1.0
+	}
+
+	spots := InitializeBoard(30, 30)
+	for r := 2; r < 30; r += 6 {
+		for c := 2; c < 30; c += 6 {
+			AddPredatorSquare(spots, r, c, 2)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		board Board
+		want  string
+	}{
+		{"empty", empty, "empty"},
+		{"flat", flat, "uniform"},
+		{"stripe", stripe, "stripes"},
+		{"spots", spots, "spots"},
+	}
+
+	for _, test := range tests {
+		stats := ComputePatternStats(test.board)
+		if got := ClassifyPattern(stats); got != test.want {
+			t.Errorf("%s: classified as %s with stats %+v, want %s", test.name, got, stats, test.want)
+		}
+	}
+}
@@ -2,35 +2,141 @@ package main
 
 import (
 	"canvas"
+	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
+	"math"
 
+	"gonum.org/v1/plot/palette"
 	"gonum.org/v1/plot/palette/moreland"
 )
 
-//DrawBoards takes a slice of Board objects as input along with a cellWidth and n parameter.
+// ColorSettings determines how a Board is colored when it is drawn.
+type ColorSettings struct {
+	colorMap       palette.ColorMap
+	quantity       string  // "prey", "predator", or "ratio" (predator / (predator + prey))
+	minVal, maxVal float64 // values at the two ends of the color map; values outside are clamped
+	legend         bool    // whether to draw a colorbar to the right of the board
+}
+
+// DefaultColorSettings returns the ColorSettings we have always used: the ratio of predator
+// to predator plus prey on a smooth blue-red color map from 0 to 1, with no legend.
+func DefaultColorSettings() ColorSettings {
+	return MakeColorSettings("bluered", "ratio", 0, 1, false)
+}
+
+// MakeColorSettings takes the name of a color map, the quantity to color by, a value range, and whether to draw a legend.
+// It returns the corresponding ColorSettings object.
+func MakeColorSettings(colorMapName, quantity string, minVal, maxVal float64, legend bool) ColorSettings {
+	if quantity != "prey" && quantity != "predator" && quantity != "ratio" {
+		panic("Error: quantity must be prey, predator, or ratio.")
+	}
+	if minVal >= maxVal {
+		panic("Error: minimum value of color range must be smaller than maximum value.")
+	}
+
+	var settings ColorSettings
+
+	settings.colorMap = ColorMapByName(colorMapName)
+	settings.colorMap.SetMin(minVal)
+	settings.colorMap.SetMax(maxVal)
+	settings.quantity = quantity
+	settings.minVal = minVal
+	settings.maxVal = maxVal
+	settings.legend = legend
+
+	return settings
+}
+
+// ColorMapByName takes the name of a color map and returns a new color map with that name.
+// Adding "-reverse" to any name reverses the color map, which is useful on white backgrounds.
+func ColorMapByName(name string) palette.ColorMap {
+	if len(name) > 8 && name[len(name)-8:] == "-reverse" {
+		return palette.Reverse(ColorMapByName(name[:len(name)-8]))
+	}
+
+	switch name {
+	case "bluered":
+		return moreland.SmoothBlueRed() // red-blue a la RNA seq
+	case "greenpurple":
+		return moreland.SmoothGreenPurple()
+	case "purpleorange":
+		return moreland.SmoothPurpleOrange()
+	case "kindlmann":
+		return moreland.Kindlmann() // on black background
+	case "extendedkindlmann":
+		return moreland.ExtendedKindlmann()
+	case "blackbody":
+		return moreland.BlackBody()
+	case "extendedblackbody":
+		return moreland.ExtendedBlackBody()
+	}
+
+	panic("Error: unknown color map " + name + ".")
+}
+
+// CellValue takes a Cell and the name of a quantity ("prey", "predator", or "ratio").
+// It returns the value of that quantity in the cell. The ratio of an empty cell is 0.
+func CellValue(cell Cell, quantity string) float64 {
+	prey := cell[0]
+	predator := cell[1]
+
+	if quantity == "prey" {
+		return prey
+	} else if quantity == "predator" {
+		return predator
+	}
+
+	if predator+prey == 0 {
+		return 0
+	}
+	return predator / (predator + prey)
+}
+
+// ValueToColor takes a value and a ColorSettings object.
+// It returns the color of the value, after clamping the value to the range of the color map.
+func ValueToColor(val float64, settings ColorSettings) color.Color {
+	val = math.Max(settings.minVal, math.Min(settings.maxVal, val))
+
+	col, err := settings.colorMap.At(val)
+
+	if err != nil {
+		panic("Error converting color!")
+	}
+
+	return col
+}
+
+//DrawBoards takes a slice of Board objects as input along with a cellWidth, an n parameter, and a ColorSettings object.
 //It returns a slice of images corresponding to drawing every nth board to a file,
 //where each cell is cellWidth x cellWidth pixels.
-func DrawBoards(boards []Board, cellWidth, n int) []image.Image {
+func DrawBoards(boards []Board, cellWidth, n int, settings ColorSettings) []image.Image {
 	imageList := make([]image.Image, 0)
 
 	// range over boards and if divisible by n, draw board and add to our list
 	for i := range boards {
 		if i%n == 0 {
-			imageList = append(imageList, DrawBoard(boards[i], cellWidth))
+			imageList = append(imageList, DrawBoard(boards[i], cellWidth, settings))
 		}
 	}
 
 	return imageList
 }
 
-//DrawBoard takes a Board objects as input along with a cellWidth and n parameter.
-//It returns an image corresponding to drawing every nth board to a file,
-//where each cell is cellWidth x cellWidth pixels.
-func DrawBoard(b Board, cellWidth int) image.Image {
+//DrawBoard takes a Board objects as input along with a cellWidth and a ColorSettings object.
+//It returns an image corresponding to drawing the board, where each cell is cellWidth x cellWidth pixels
+//colored according to settings, followed by a colorbar if settings asks for a legend.
+func DrawBoard(b Board, cellWidth int, settings ColorSettings) image.Image {
 	// need to know how many pixels wide and tall to make our image
 
 	height := len(b) * cellWidth
-	width := len(b[0]) * cellWidth
+	boardWidth := len(b[0]) * cellWidth
+	width := boardWidth
+
+	if settings.legend {
+		width += LegendWidth(height)
+	}
 
 	// think of a canvas as a PowerPoint slide that we draw on
 	c := canvas.CreateNewCanvas(width, height)
@@ -39,38 +145,72 @@ func DrawBoard(b Board, cellWidth int) image.Image {
 
 	for i := range b {
 		for j := range b[i] {
-			prey := b[i][j][0]
-			predator := b[i][j][1]
-
 			// we will color each cell according to a color map.
-
-			val := predator / (predator + prey)
-
-			//colorMap := palette.Reverse(moreland.Kindlmann()) // on white background
-			//colorMap := moreland.Kindlmann() // on black background
-			colorMap := moreland.SmoothBlueRed() // red-blue a la RNA seq
-
-			//set min and max value of color map
-			colorMap.SetMin(0)
-			colorMap.SetMax(1)
-
-			//find the color associated with the value predator / (predator + prey)
-			color, err := colorMap.At(val)
-
-			if err != nil {
-				panic("Error converting color!")
-			}
+			val := CellValue(b[i][j], settings.quantity)
 
 			// draw a rectangle in right place with this color
-			c.SetFillColor(color)
+			c.SetFillColor(ValueToColor(val, settings))
 
-			x := i * cellWidth
-			y := j * cellWidth
+			x := j * cellWidth
+			y := i * cellWidth
 			c.ClearRect(x, y, x+cellWidth, y+cellWidth)
 			c.Fill()
 		}
 	}
 
+	if settings.legend {
+		DrawLegend(&c, boardWidth, height, settings)
+	}
+
 	// canvas has an image field that we should return
 	return c.GetImage()
 }
+
+// LegendWidth takes the height of a board in pixels and returns the width in pixels of the area
+// to the right of the board in which its colorbar and labels are drawn.
+func LegendWidth(height int) int {
+	return BarWidth(height) + 60
+}
+
+// BarWidth takes the height of a board in pixels and returns the width of its colorbar in pixels.
+func BarWidth(height int) int {
+	return int(math.Max(10, float64(height)/20))
+}
+
+// DrawLegend takes a pointer to a Canvas, the width and height of the board drawn on it, and a ColorSettings object.
+// It draws a vertical colorbar to the right of the board, running from the maximum value at the top
+// to the minimum value at the bottom, and labels both ends with their values.
+func DrawLegend(c *canvas.Canvas, boardWidth, height int, settings ColorSettings) {
+	legendWidth := LegendWidth(height)
+	barWidth := BarWidth(height)
+
+	// white background behind the legend
+	c.SetFillColor(canvas.MakeColor(255, 255, 255))
+	c.ClearRect(boardWidth, 0, boardWidth+legendWidth, height)
+	c.Fill()
+
+	margin := 5
+	barX := boardWidth + margin
+	barTop := margin
+	barBottom := height - margin
+
+	// one horizontal stripe of the bar per pixel row
+	for y := barTop; y < barBottom; y++ {
+		fraction := float64(barBottom-1-y) / math.Max(1, float64(barBottom-1-barTop))
+		val := settings.minVal + fraction*(settings.maxVal-settings.minVal)
+		c.SetFillColor(ValueToColor(val, settings))
+		c.ClearRect(barX, y, barX+barWidth, y+1)
+		c.Fill()
+	}
+
+	// label the ends of the bar, if the canvas lets us draw on its pixels
+	img, ok := c.GetImage().(draw.Image)
+	if !ok {
+		return
+	}
+
+	black := color.RGBA{0, 0, 0, 255}
+	labelX := barX + barWidth + margin
+	DrawLabel(img, fmt.Sprintf("%.2f", settings.maxVal), labelX, barTop, 2, black)
+	DrawLabel(img, fmt.Sprintf("%.2f", settings.minVal), labelX, barBottom-10, 2, black)
+}
@@ -1,3 +1,163 @@
 package main
 
-//place your functions from the assignment here.
+// SimulateGrayScott takes an initial Board, a number of generations, feed and kill rates,
+// diffusion rates for prey and predator, and a 3 x 3 Laplacian kernel.
+// It returns a slice of numGens + 1 Boards resulting from running the Gray-Scott
+// reaction-diffusion model for numGens generations, starting with initialBoard.
+func SimulateGrayScott(initialBoard Board, numGens int, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64) []Board {
+	boards := make([]Board, numGens+1)
+	boards[0] = initialBoard
+
+	for i := 1; i <= numGens; i++ {
+		boards[i] = UpdateBoard(boards[i-1], feedRate, killRate, preyDiffusionRate, predatorDiffusionRate, kernel)
+	}
+
+	return boards
+}
+
+// UpdateBoard takes a Board along with the parameters of the Gray-Scott model.
+// It returns a new Board corresponding to a single generation of reaction and diffusion.
+func UpdateBoard(currentBoard Board, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64) Board {
+	numRows := CountRows(currentBoard)
+	numCols := CountCols(currentBoard)
+	newBoard := InitializeBoard(numRows, numCols)
+
+	for r := range currentBoard {
+		for c := range currentBoard[r] {
+			newBoard[r][c] = UpdateCell(currentBoard, r, c, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate, kernel)
+		}
+	}
+
+	return newBoard
+}
+
+// UpdateCell takes a Board, a row and column index, and the parameters of the Gray-Scott model.
+// It returns the Cell at (row, col) in the next generation, which is the current Cell
+// plus the change due to diffusion plus the change due to reactions.
+func UpdateCell(currentBoard Board, row, col int, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64) Cell {
+	return UpdateCellFrom(BoardNeighbors(currentBoard), row, col, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate, kernel)
+}
+
+// Neighbors is a function that takes a row and column index and returns the Cell there,
+// along with false if there is no cell there.
+type Neighbors func(row, col int) (Cell, bool)
+
+// BoardNeighbors takes a Board and returns the Neighbors that reads its cells, with no cells outside the board.
+func BoardNeighbors(currentBoard Board) Neighbors {
+	return func(row, col int) (Cell, bool) {
+		if !InField(currentBoard, row, col) {
+			return Cell{}, false
+		}
+		return currentBoard[row][col], true
+	}
+}
+
+// UpdateCellFrom takes Neighbors, a row and column index, and the parameters of the Gray-Scott model,
+// and returns the Cell at (row, col) in the next generation as UpdateCell does, reading cells through the Neighbors.
+func UpdateCellFrom(at Neighbors, row, col int, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64) Cell {
+	currentCell, _ := at(row, col)
+	diffusionValues := ChangeDueToDiffusionFrom(at, row, col, preyDiffusionRate, predatorDiffusionRate, kernel)
+	reactionValues := ChangeDueToReactions(currentCell, feedRate, killRate)
+
+	return SumCells(currentCell, diffusionValues, reactionValues)
+}
+
+// ChangeDueToReactions takes a Cell along with feed and kill rates.
+// It returns a Cell holding the change in prey and predator concentration due to reactions.
+// Prey is fed at rate feedRate * (1 - prey) and consumed by the reaction prey + 2 predator -> 3 predator,
+// while predator is produced by that reaction and removed at rate killRate * predator.
+func ChangeDueToReactions(currentCell Cell, feedRate, killRate float64) Cell {
+	var change Cell
+
+	prey := currentCell[0]
+	predator := currentCell[1]
+
+	change[0] = feedRate*(1-prey) - prey*predator*predator
+	change[1] = -killRate*predator + prey*predator*predator
+
+	return change
+}
+
+// ChangeDueToDiffusion takes a Board, a row and column index, diffusion rates for prey and predator,
+// and a Laplacian kernel.
+// It returns a Cell holding the change in prey and predator concentration at (row, col) due to diffusion.
+func ChangeDueToDiffusion(currentBoard Board, row, col int, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64) Cell {
+	return ChangeDueToDiffusionFrom(BoardNeighbors(currentBoard), row, col, preyDiffusionRate, predatorDiffusionRate, kernel)
+}
+
+// ChangeDueToDiffusionFrom takes Neighbors, a row and column index, diffusion rates for prey and predator,
+// and a Laplacian kernel, and returns the change due to diffusion as ChangeDueToDiffusion does.
+func ChangeDueToDiffusionFrom(at Neighbors, row, col int, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64) Cell {
+	var change Cell
+
+	laplacian := ComputeLaplacianFrom(at, row, col, kernel)
+
+	change[0] = preyDiffusionRate * laplacian[0]
+	change[1] = predatorDiffusionRate * laplacian[1]
+
+	return change
+}
+
+// ComputeLaplacian takes a Board, a row and column index, and a 3 x 3 kernel.
+// It returns a Cell holding the discrete Laplacian of the prey and predator concentrations at (row, col),
+// obtained by weighting the cell and its eight neighbors by the kernel.
+// Neighbors that fall outside the board are ignored.
+func ComputeLaplacian(currentBoard Board, row, col int, kernel [3][3]float64) Cell {
+	return ComputeLaplacianFrom(BoardNeighbors(currentBoard), row, col, kernel)
+}
+
+// ComputeLaplacianFrom takes Neighbors, a row and column index, and a 3 x 3 kernel, and returns the discrete
+// Laplacian at (row, col) as ComputeLaplacian does. Neighbors that the Neighbors report missing are ignored,
+// so the boundary is whatever the Neighbors make it.
+func ComputeLaplacianFrom(at Neighbors, row, col int, kernel [3][3]float64) Cell {
+	var sum Cell
+
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			if neighbor, ok := at(row+i, col+j); ok {
+				sum[0] += neighbor[0] * kernel[i+1][j+1]
+				sum[1] += neighbor[1] * kernel[i+1][j+1]
+			}
+		}
+	}
+
+	return sum
+}
+
+// SumCells takes an arbitrary number of Cells and returns their componentwise sum.
+func SumCells(cells ...Cell) Cell {
+	var sum Cell
+
+	for _, c := range cells {
+		sum[0] += c[0]
+		sum[1] += c[1]
+	}
+
+	return sum
+}
+
+// InField takes a Board and a row and column index.
+// It returns true if (row, col) is a valid cell of the board and false otherwise.
+func InField(currentBoard Board, row, col int) bool {
+	if row < 0 || row >= CountRows(currentBoard) {
+		return false
+	}
+	if col < 0 || col >= CountCols(currentBoard) {
+		return false
+	}
+	return true
+}
+
+// CountRows takes a Board and returns its number of rows.
+func CountRows(currentBoard Board) int {
+	return len(currentBoard)
+}
+
+// CountCols takes a Board and returns its number of columns.
+// It panics if the board has no rows.
+func CountCols(currentBoard Board) int {
+	if CountRows(currentBoard) == 0 {
+		panic("Error: empty board given to CountCols.")
+	}
+	return len(currentBoard[0])
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// CellsClose takes two Cells and a tolerance and returns true if both concentrations agree within the tolerance.
+func CellsClose(a, b Cell, tolerance float64) bool {
+	return math.Abs(a[0]-b[0]) <= tolerance && math.Abs(a[1]-b[1]) <= tolerance
+}
+
+// TestComputeLaplacianConstant tests that the Laplacian of a constant board is zero in the interior, and that at
+// the edges, where neighbors outside the board are ignored, it is the constant times the weights that are left.
+func TestComputeLaplacianConstant(t *testing.T) {
+	kernel := DefaultKernel()
+	b := InitializeBoard(5, 6)
+	for r := range b {
+		for c := range b[r] {
+			b[r][c] = Cell{0.8, 0.3}
+		}
+	}
+
+	for r := 1; r < 4; r++ {
+		for c := 1; c < 5; c++ {
+			if l := ComputeLaplacian(b, r, c, kernel); !CellsClose(l, Cell{0, 0}, 1e-12) {
+				t.Errorf("Laplacian at interior cell (%d, %d) = %v, want 0", r, c, l)
+			}
+		}
+	}
+
+	// a corner keeps the center, two edges and one diagonal: -1 + 0.2 + 0.2 + 0.05 = -0.55
+	if l := ComputeLaplacian(b, 0, 0, kernel); !CellsClose(l, Cell{0.8 * -0.55, 0.3 * -0.55}, 1e-12) {
+		t.Errorf("Laplacian at corner = %v, want %v", l, Cell{0.8 * -0.55, 0.3 * -0.55})
+	}
+	// an edge cell loses one row of the kernel: -1 + 3 * 0.2 + 2 * 0.05 = -0.3
+	if l := ComputeLaplacian(b, 4, 2, kernel); !CellsClose(l, Cell{0.8 * -0.3, 0.3 * -0.3}, 1e-12) {
+		t.Errorf("Laplacian at bottom edge = %v, want %v", l, Cell{0.8 * -0.3, 0.3 * -0.3})
+	}
+}
+
+// TestUpdateCell tests one generation of a 3 x 3 board full of prey with predators in the center cell
+// against values computed by hand.
+func TestUpdateCell(t *testing.T) {
+	b := InitializeBoard(3, 3)
+	b[1][1][1] = 1.0
+	feedRate, killRate := 0.034, 0.095
+	preyDiffusionRate, predatorDiffusionRate := 0.2, 0.1
+
+	tests := []struct {
+		row, col int
+		want     Cell
+	}{
+		// prey doesn't diffuse and is eaten entirely; predator diffuses out (-0.1) and grows by 1 - 0.095
+		{1, 1, Cell{0, 1.805}},
+		// the corner loses prey to the missing neighbors (0.2 * -0.55) and gains predator from the diagonal (0.1 * 0.05)
+		{0, 0, Cell{0.89, 0.005}},
+		// the edge loses prey (0.2 * -0.3) and gains predator from the center below it (0.1 * 0.2)
+		{0, 1, Cell{0.94, 0.02}},
+	}
+
+	for _, test := range tests {
+		got := UpdateCell(b, test.row, test.col, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate, DefaultKernel())
+		if !CellsClose(got, test.want, 1e-12) {
+			t.Errorf("UpdateCell at (%d, %d) = %v, want %v", test.row, test.col, got, test.want)
+		}
+	}
+
+	// UpdateBoard and SimulateGrayScott must update every cell the same way
+	boards := SimulateGrayScott(b, 1, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate, DefaultKernel())
+	if len(boards) != 2 {
+		t.Fatalf("SimulateGrayScott returned %d boards, want 2", len(boards))
+	}
+	for _, test := range tests {
+		if got := boards[1][test.row][test.col]; !CellsClose(got, test.want, 1e-12) {
+			t.Errorf("SimulateGrayScott cell (%d, %d) = %v, want %v", test.row, test.col, got, test.want)
+		}
+	}
+}
+
+// TestInitializeRandomSeeds tests that the same seed gives the same board and that a different seed gives a different one.
+func TestInitializeRandomSeeds(t *testing.T) {
+	a := InitializeRandomSeeds(40, 30, 10, 3, 7)
+	b := InitializeRandomSeeds(40, 30, 10, 3, 7)
+	c := InitializeRandomSeeds(40, 30, 10, 3, 8)
+
+	same, differentFromC := true, false
+	numPredators := 0
+	for r := range a {
+		for col := range a[r] {
+			if a[r][col] != b[r][col] {
+				same = false
+			}
+			if a[r][col] != c[r][col] {
+				differentFromC = true
+			}
+			if a[r][col][1] == 1.0 {
+				numPredators++
+			}
+		}
+	}
+
+	if !same {
+		t.Errorf("InitializeRandomSeeds gave different boards for the same seed")
+	}
+	if !differentFromC {
+		t.Errorf("InitializeRandomSeeds gave the same board for seeds 7 and 8")
+	}
+	if numPredators == 0 {
+		t.Errorf("InitializeRandomSeeds placed no predators")
+	}
+}
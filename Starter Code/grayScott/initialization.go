@@ -0,0 +1,59 @@
+package main
+
+import (
+	"rng"
+)
+
+// InitializeBoard takes a number of rows and columns.
+// It returns a numRows x numCols Board in which every cell is full of prey and has no predators.
+func InitializeBoard(numRows, numCols int) Board {
+	b := make(Board, numRows)
+
+	for r := range b {
+		b[r] = make([]Cell, numCols)
+		for c := range b[r] {
+			b[r][c][0] = 1.0
+		}
+	}
+
+	return b
+}
+
+// InitializeCentralSquare takes a number of rows and columns along with a square width.
+// It returns a Board full of prey, in which a square of the given width at the center of the board
+// is also full of predators.
+func InitializeCentralSquare(numRows, numCols, squareWidth int) Board {
+	b := InitializeBoard(numRows, numCols)
+
+	AddPredatorSquare(b, numRows/2-squareWidth/2, numCols/2-squareWidth/2, squareWidth)
+
+	return b
+}
+
+// InitializeRandomSeeds takes a number of rows and columns, a number of seeds, a seed width, and the seed of
+// the random numbers. It returns a Board full of prey, in which numSeeds squares of predators, each seedWidth
+// cells wide, have been placed at random positions.
+func InitializeRandomSeeds(numRows, numCols, numSeeds, seedWidth int, seed int64) Board {
+	b := InitializeBoard(numRows, numCols)
+	r := rng.New(seed)
+
+	for i := 0; i < numSeeds; i++ {
+		row := r.Intn(numRows)
+		col := r.Intn(numCols)
+		AddPredatorSquare(b, row, col, seedWidth)
+	}
+
+	return b
+}
+
+// AddPredatorSquare takes a Board, the row and column of a top left corner, and a square width.
+// It fills every in-field cell of the square with predators.
+func AddPredatorSquare(b Board, row, col, squareWidth int) {
+	for r := row; r < row+squareWidth; r++ {
+		for c := col; c < col+squareWidth; c++ {
+			if InField(b, r, c) {
+				b[r][c][1] = 1.0
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+)
+
+// WriteAtlasToFile takes a grid of AtlasEntry objects and a file name.
+// It writes one line per entry to a CSV file, giving the entry's parameters, statistics, and regime.
+func WriteAtlasToFile(atlas [][]AtlasEntry, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, "feedRate,pearsonK,mean,stdDev,spotCount,anisotropy,coverage,regime")
+
+	for i := range atlas {
+		for _, entry := range atlas[i] {
+			s := entry.stats
+			fmt.Fprintf(writer, "%g,%g,%g,%g,%d,%g,%g,%s\n", entry.feedRate, entry.pearsonK, s.mean, s.stdDev, s.spotCount, s.anisotropy, s.coverage, entry.regime)
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SaveImageToPNG takes an image and a file name and writes the image to a PNG file.
+func SaveImageToPNG(img image.Image, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	err = png.Encode(file, img)
+	if err != nil {
+		panic(err)
+	}
+}
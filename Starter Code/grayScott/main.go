@@ -2,8 +2,223 @@ package main
 
 import (
 	"fmt"
+	"gifhelper"
+	"os"
+	"rng"
+	"runtime"
+	"stencil"
+	"strconv"
+	"strings"
 )
 
 func main() {
 	fmt.Println("Let's hack the Gray-Scott model!")
+
+	// "atlas" as the first CLA sweeps over feed rates and Pearson's k instead of running one simulation
+	if len(os.Args) > 1 && os.Args[1] == "atlas" {
+		RunAtlas()
+		return
+	}
+
+	// CLAs: initial pattern (as in ParsePattern), numRows, numCols, numGens,
+	// feedRate, killRate, cellWidth, imageFrequency, and optionally
+	// color map name, quantity ("prey", "predator", or "ratio"), the values mapped to the two ends
+	// of the color map, and whether to draw a legend
+	if len(os.Args) != 9 && len(os.Args) != 14 {
+		panic("Error: incorrect number of command line arguments.")
+	}
+
+	pattern, seed := ParsePattern(os.Args[1])
+
+	numRows, err := strconv.Atoi(os.Args[2])
+	Check(err)
+
+	numCols, err := strconv.Atoi(os.Args[3])
+	Check(err)
+
+	numGens, err := strconv.Atoi(os.Args[4])
+	Check(err)
+
+	feedRate, err := strconv.ParseFloat(os.Args[5], 64)
+	Check(err)
+
+	killRate, err := strconv.ParseFloat(os.Args[6], 64)
+	Check(err)
+
+	cellWidth, err := strconv.Atoi(os.Args[7])
+	Check(err)
+
+	imageFrequency, err := strconv.Atoi(os.Args[8])
+	Check(err)
+
+	if numRows <= 0 || numCols <= 0 || cellWidth <= 0 || imageFrequency <= 0 {
+		panic("Error: board dimensions, cellWidth and imageFrequency must be positive.")
+	}
+
+	settings := DefaultColorSettings()
+
+	if len(os.Args) == 14 {
+		minVal, err := strconv.ParseFloat(os.Args[11], 64)
+		Check(err)
+
+		maxVal, err := strconv.ParseFloat(os.Args[12], 64)
+		Check(err)
+
+		if minVal >= maxVal {
+			panic("Error: minimum value of color range must be smaller than maximum value.")
+		}
+
+		legend, err := strconv.ParseBool(os.Args[13])
+		Check(err)
+
+		settings = MakeColorSettings(os.Args[9], os.Args[10], minVal, maxVal, legend)
+	}
+
+	fmt.Println("Command line arguments read!")
+
+	var initialBoard Board
+
+	if pattern == "square" {
+		initialBoard = InitializeCentralSquare(numRows, numCols, 10)
+	} else {
+		initialBoard = InitializeRandomSeeds(numRows, numCols, 20, 5, seed)
+	}
+
+	// diffusion rates and the Laplacian kernel are standard choices for this model
+	preyDiffusionRate := 0.2
+	predatorDiffusionRate := 0.1
+
+	kernel := DefaultKernel()
+
+	numProcs := runtime.NumCPU()
+
+	fmt.Println("Simulating Gray-Scott now.")
+
+	// only every imageFrequency-th board is kept, so we draw all of them
+	boards := SimulateGrayScottParallel(initialBoard, numGens, imageFrequency, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate, kernel, stencil.Fixed, numProcs)
+
+	fmt.Println("Simulation run! Drawing boards.")
+
+	imageList := DrawBoards(boards, cellWidth, 1, settings)
+
+	fmt.Println("Boards drawn! Now generating GIF.")
+
+	outputFile := "grayScott_" + pattern
+	gifhelper.ImagesToGIF(imageList, outputFile)
+
+	fmt.Println("GIF drawn!")
+}
+
+// RunAtlas parses the CLAs of the atlas command, sweeps over a grid of feed rates and values of Pearson's k,
+// and writes a mosaic of the final boards to atlas.png along with their statistics to atlas.csv.
+// Predator is removed at rate feedRate + k, rather than at the killRate taken by a single simulation.
+func RunAtlas() {
+	// CLAs: atlas, numRows, numCols, numGens, minFeed, maxFeed, numFeeds, minK, maxK, numKs, cellWidth,
+	// and optionally the seed of the random initial board, as in rng.ParseSeed
+	if len(os.Args) != 12 && len(os.Args) != 13 {
+		panic("Error: incorrect number of command line arguments for atlas.")
+	}
+
+	numRows, err := strconv.Atoi(os.Args[2])
+	Check(err)
+
+	numCols, err := strconv.Atoi(os.Args[3])
+	Check(err)
+
+	numGens, err := strconv.Atoi(os.Args[4])
+	Check(err)
+
+	minFeed, err := strconv.ParseFloat(os.Args[5], 64)
+	Check(err)
+
+	maxFeed, err := strconv.ParseFloat(os.Args[6], 64)
+	Check(err)
+
+	numFeeds, err := strconv.Atoi(os.Args[7])
+	Check(err)
+
+	minK, err := strconv.ParseFloat(os.Args[8], 64)
+	Check(err)
+
+	maxK, err := strconv.ParseFloat(os.Args[9], 64)
+	Check(err)
+
+	numKs, err := strconv.Atoi(os.Args[10])
+	Check(err)
+
+	cellWidth, err := strconv.Atoi(os.Args[11])
+	Check(err)
+
+	if numRows <= 0 || numCols <= 0 || numFeeds <= 0 || numKs <= 0 || cellWidth <= 0 {
+		panic("Error: board dimensions, grid sizes and cellWidth must be positive.")
+	}
+
+	seedString := "time"
+	if len(os.Args) == 13 {
+		seedString = os.Args[12]
+	}
+	seed, err := rng.ParseSeed(seedString)
+	Check(err)
+	fmt.Println("Seed:", seed)
+
+	fmt.Println("Command line arguments read!")
+
+	// every simulation starts from the same random seeds so that only the parameters differ
+	initialBoard := InitializeRandomSeeds(numRows, numCols, 20, 5, seed)
+
+	feedRates := LinearRange(minFeed, maxFeed, numFeeds)
+	pearsonKs := LinearRange(minK, maxK, numKs)
+
+	fmt.Println("Sweeping", numFeeds*numKs, "parameter pairs.")
+
+	atlas := SweepParameters(initialBoard, numGens, feedRates, pearsonKs, 0.2, 0.1, DefaultKernel(), runtime.NumCPU())
+
+	fmt.Println("Sweep complete! Drawing atlas.")
+
+	SaveImageToPNG(DrawAtlas(atlas, cellWidth), "atlas.png")
+	WriteAtlasToFile(atlas, "atlas.csv")
+
+	fmt.Println("Atlas written to atlas.png and atlas.csv.")
+}
+
+// ParsePattern takes an initial pattern from the command line: "square", "random", or "random:SEED" to repeat an
+// earlier random run. It returns the pattern and the seed of its random numbers, which comes from the clock
+// if none is given, as in rng.ParseSeed. The seed of a random pattern is printed so that the run can be repeated.
+func ParsePattern(s string) (string, int64) {
+	pattern, seedString, hasSeed := strings.Cut(s, ":")
+	if !hasSeed {
+		seedString = "time"
+	}
+
+	if pattern != "square" && pattern != "random" {
+		panic("Error: initial pattern must be square or random.")
+	}
+	if pattern == "square" && hasSeed {
+		panic("Error: only a random pattern takes a seed.")
+	}
+
+	seed, err := rng.ParseSeed(seedString)
+	Check(err)
+
+	if pattern == "random" {
+		fmt.Println("Seed:", seed)
+	}
+
+	return pattern, seed
+}
+
+// DefaultKernel returns the standard 3 x 3 kernel used to compute the Laplacian.
+func DefaultKernel() [3][3]float64 {
+	var kernel [3][3]float64
+	kernel[0] = [3]float64{.05, .2, .05}
+	kernel[1] = [3]float64{.2, -1.0, .2}
+	kernel[2] = [3]float64{.05, .2, .05}
+	return kernel
+}
+
+// Check panics if err is not nil.
+func Check(err error) {
+	if err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"glyphs"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// regimeColors gives the color of the frame drawn around each tile of an atlas, by regime.
+var regimeColors = map[string]color.Color{
+	"empty":   color.RGBA{0, 0, 0, 255},
+	"uniform": color.RGBA{128, 128, 128, 255},
+	"spots":   color.RGBA{230, 159, 0, 255},
+	"stripes": color.RGBA{0, 158, 115, 255},
+	"mixed":   color.RGBA{204, 121, 167, 255},
+}
+
+// DrawAtlas takes a grid of AtlasEntry objects and a cellWidth.
+// It returns a mosaic image in which the final board of every entry is drawn as a tile,
+// framed in the color of its regime, with feed rates labelled down the left side
+// and kill rates labelled along the top.
+func DrawAtlas(atlas [][]AtlasEntry, cellWidth int) image.Image {
+	numRows := len(atlas)
+	numCols := len(atlas[0])
+
+	tileHeight := CountRows(atlas[0][0].finalBoard) * cellWidth
+	tileWidth := CountCols(atlas[0][0].finalBoard) * cellWidth
+
+	frame := 3       // width of the regime frame around each tile
+	gap := 4         // space between neighboring tiles
+	labelScale := 2  // each glyph pixel is labelScale x labelScale pixels
+	marginLeft := 44 // room for feed rate labels
+	marginTop := 20  // room for kill rate labels
+
+	cellW := tileWidth + 2*frame + gap
+	cellH := tileHeight + 2*frame + gap
+
+	width := marginLeft + numCols*cellW
+	height := marginTop + numRows*cellH
+
+	mosaic := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(mosaic, mosaic.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	black := color.RGBA{0, 0, 0, 255}
+	settings := DefaultColorSettings()
+
+	for j := 0; j < numCols; j++ {
+		label := fmt.Sprintf("k=%.3f", atlas[0][j].pearsonK)
+		DrawLabel(mosaic, label, marginLeft+j*cellW+frame, 4, labelScale, black)
+	}
+
+	for i := 0; i < numRows; i++ {
+		label := fmt.Sprintf("%.3f", atlas[i][0].feedRate)
+		DrawLabel(mosaic, label, 2, marginTop+i*cellH+frame+tileHeight/2, labelScale, black)
+
+		for j := 0; j < numCols; j++ {
+			x := marginLeft + j*cellW
+			y := marginTop + i*cellH
+
+			frameColor, ok := regimeColors[atlas[i][j].regime]
+			if !ok {
+				frameColor = black
+			}
+			frameRect := image.Rect(x, y, x+tileWidth+2*frame, y+tileHeight+2*frame)
+			draw.Draw(mosaic, frameRect, image.NewUniform(frameColor), image.Point{}, draw.Src)
+
+			tile := DrawBoard(atlas[i][j].finalBoard, cellWidth, settings)
+			tileRect := image.Rect(x+frame, y+frame, x+frame+tileWidth, y+frame+tileHeight)
+			draw.Draw(mosaic, tileRect, tile, tile.Bounds().Min, draw.Src)
+		}
+	}
+
+	return mosaic
+}
+
+// DrawLabel takes an image, a string, the position of its top left corner, a scale, and a color.
+// It draws the string onto the image in the 3 x 5 font of glyphs, skipping characters that have no glyph.
+func DrawLabel(img draw.Image, label string, x, y, scale int, col color.Color) {
+	glyphs.Draw(label, x, y, scale, func(x1, y1, x2, y2 int) {
+		draw.Draw(img, image.Rect(x1, y1, x2, y2), image.NewUniform(col), image.Point{}, draw.Src)
+	})
+}
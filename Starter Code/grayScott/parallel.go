@@ -0,0 +1,40 @@
+package main
+
+import (
+	"stencil"
+)
+
+// SimulateGrayScottParallel takes an initial Board, a number of generations, a frequency, the parameters
+// of the Gray-Scott model, a boundary condition, and a number of processors.
+// It runs the Gray-Scott model for numGens generations, dividing the rows of the board over numProcs
+// goroutines, and returns copies of every frequency-th board (including the initial board).
+// Only two boards are updated during the simulation, so memory is used only for the boards returned.
+func SimulateGrayScottParallel(initialBoard Board, numGens, frequency int, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64, boundary stencil.Boundary, numProcs int) []Board {
+	if frequency <= 0 {
+		panic("Error: nonpositive frequency given to SimulateGrayScottParallel.")
+	}
+
+	boards := make([]Board, 0, numGens/frequency+1)
+
+	e := stencil.NewEngine(initialBoard, boundary, numProcs)
+	rule := GrayScottRule(feedRate, killRate, preyDiffusionRate, predatorDiffusionRate, kernel)
+
+	boards = append(boards, e.Snapshot())
+
+	for i := 1; i <= numGens; i++ {
+		e.Step(rule)
+		if i%frequency == 0 {
+			boards = append(boards, e.Snapshot())
+		}
+	}
+
+	return boards
+}
+
+// GrayScottRule takes the parameters of the Gray-Scott model and returns a stencil rule
+// that updates a single cell, in the same way as UpdateCell, reading its neighbors across the stencil's boundary.
+func GrayScottRule(feedRate, killRate, preyDiffusionRate, predatorDiffusionRate float64, kernel [3][3]float64) stencil.Rule[Cell] {
+	return func(src *stencil.Reader[Cell], row, col int) Cell {
+		return UpdateCellFrom(src.At, row, col, feedRate, killRate, preyDiffusionRate, predatorDiffusionRate, kernel)
+	}
+}
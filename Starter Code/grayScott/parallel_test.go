@@ -0,0 +1,56 @@
+package main
+
+import (
+	"stencil"
+	"testing"
+)
+
+// TestParallelMatchesSerial tests that SimulateGrayScottParallel with a fixed boundary gives exactly the boards of
+// SimulateGrayScott, and that with a periodic boundary every number of processors gives the same boards.
+func TestParallelMatchesSerial(t *testing.T) {
+	numGens := 30
+	feedRate, killRate := 0.034, 0.095
+	initialBoard := InitializeRandomSeeds(31, 23, 6, 4, 3)
+
+	serial := SimulateGrayScott(initialBoard, numGens, feedRate, killRate, 0.2, 0.1, DefaultKernel())
+	periodic := SimulateGrayScottParallel(initialBoard, numGens, 1, feedRate, killRate, 0.2, 0.1, DefaultKernel(), stencil.Periodic, 1)
+
+	for _, numProcs := range []int{1, 2, 3, 8, 100} {
+		fixed := SimulateGrayScottParallel(initialBoard, numGens, 1, feedRate, killRate, 0.2, 0.1, DefaultKernel(), stencil.Fixed, numProcs)
+		if len(fixed) != len(serial) {
+			t.Fatalf("%d processors: got %d boards, want %d", numProcs, len(fixed), len(serial))
+		}
+		if i, r, c, ok := FirstDifference(serial, fixed); !ok {
+			t.Errorf("%d processors, fixed boundary: generation %d cell (%d, %d) = %v, want %v", numProcs, i, r, c, fixed[i][r][c], serial[i][r][c])
+		}
+
+		wrapped := SimulateGrayScottParallel(initialBoard, numGens, 1, feedRate, killRate, 0.2, 0.1, DefaultKernel(), stencil.Periodic, numProcs)
+		if i, r, c, ok := FirstDifference(periodic, wrapped); !ok {
+			t.Errorf("%d processors, periodic boundary: generation %d cell (%d, %d) = %v, want %v", numProcs, i, r, c, wrapped[i][r][c], periodic[i][r][c])
+		}
+	}
+
+	// keeping every tenth board returns the initial board and generations 10, 20 and 30
+	kept := SimulateGrayScottParallel(initialBoard, numGens, 10, feedRate, killRate, 0.2, 0.1, DefaultKernel(), stencil.Fixed, 4)
+	if _, r, c, ok := FirstDifference([]Board{serial[0], serial[10], serial[20], serial[30]}, kept); !ok || len(kept) != 4 {
+		t.Errorf("keeping every tenth board: got %d boards, first difference at (%d, %d)", len(kept), r, c)
+	}
+}
+
+// FirstDifference takes two slices of Boards and returns the generation, row and column of the first cell in which
+// they differ along with false, or true if they are identical.
+func FirstDifference(a, b []Board) (int, int, int, bool) {
+	for i := range a {
+		if i >= len(b) {
+			return i, 0, 0, false
+		}
+		for r := range a[i] {
+			for c := range a[i][r] {
+				if a[i][r][c] != b[i][r][c] {
+					return i, r, c, false
+				}
+			}
+		}
+	}
+	return 0, 0, 0, true
+}
@@ -0,0 +1,165 @@
+// Package montecarlo provides a parallel engine for Monte Carlo experiments, in which a random trial
+// is repeated many times and the mean of its numeric outcome is estimated.
+//
+// A trial is any function that draws from a random source and returns a payoff, such as the money won
+// by a single bet. The trials are split over several goroutines, each drawing from its own stream derived
+// from a master seed with package rng, so that the same seed and number of workers always give the same
+// estimate. Every worker keeps a running Summary of its payoffs, and the Summaries are merged once the workers
+// are done, so no payoff is ever stored. Besides the mean, a Summary reports the sample variance, the standard
+// error of the mean, and normal confidence intervals.
+package montecarlo
+
+import (
+	"math"
+	"math/rand"
+	"rng"
+	"workpool"
+)
+
+// Trial plays one random trial with numbers drawn from r and returns its payoff.
+// A Trial may be called from several goroutines at once, each with its own r.
+type Trial func(r *rand.Rand) float64
+
+// Summary holds the running statistics of a sequence of payoffs. It uses Welford's method, which keeps
+// the mean and the sum of squared deviations from it, and so stays accurate over billions of payoffs.
+// The zero Summary holds no payoffs.
+type Summary struct {
+	n    int
+	mean float64
+	m2   float64 // the sum of squared deviations of the payoffs from their mean
+}
+
+// Add adds a payoff to the Summary.
+func (s *Summary) Add(x float64) {
+	s.n++
+	delta := x - s.mean
+	s.mean += delta / float64(s.n)
+	s.m2 += delta * (x - s.mean)
+}
+
+// Merge adds all the payoffs of another Summary to this one, as if they had been added one at a time.
+func (s *Summary) Merge(other Summary) {
+	if other.n == 0 {
+		return
+	}
+	if s.n == 0 {
+		*s = other
+		return
+	}
+
+	n := s.n + other.n
+	delta := other.mean - s.mean
+	s.m2 += other.m2 + delta*delta*float64(s.n)*float64(other.n)/float64(n)
+	s.mean += delta * float64(other.n) / float64(n)
+	s.n = n
+}
+
+// N returns the number of payoffs in the Summary.
+func (s Summary) N() int {
+	return s.n
+}
+
+// Mean returns the mean of the payoffs, or 0 if there are none.
+func (s Summary) Mean() float64 {
+	return s.mean
+}
+
+// Variance returns the sample variance of the payoffs, dividing by n - 1, or 0 if there are fewer than two.
+func (s Summary) Variance() float64 {
+	if s.n < 2 {
+		return 0
+	}
+	return s.m2 / float64(s.n-1)
+}
+
+// StdDev returns the sample standard deviation of the payoffs.
+func (s Summary) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// StdErr returns the standard error of the mean, the standard deviation divided by the square root of n.
+// It is infinite if there are fewer than two payoffs, since the error can't yet be estimated.
+func (s Summary) StdErr() float64 {
+	if s.n < 2 {
+		return math.Inf(1)
+	}
+	return s.StdDev() / math.Sqrt(float64(s.n))
+}
+
+// ConfidenceInterval takes a confidence level between 0 and 1, such as 0.95, and returns the lower and upper ends
+// of the normal confidence interval for the mean, mean -/+ z * StdErr, where z is the quantile of the standard
+// normal distribution with that much probability between -z and z.
+func (s Summary) ConfidenceInterval(level float64) (float64, float64) {
+	if level <= 0 || level >= 1 {
+		panic("Error: confidence level must be strictly between 0 and 1.")
+	}
+
+	halfWidth := ZScore(level) * s.StdErr()
+	return s.mean - halfWidth, s.mean + halfWidth
+}
+
+// ZScore takes a confidence level between 0 and 1 and returns the z with that much probability between -z and z
+// under the standard normal distribution, for example about 1.96 for 0.95.
+func ZScore(level float64) float64 {
+	return math.Sqrt2 * math.Erfinv(level)
+}
+
+// Run takes a Trial, a number of trials, a number of processors, and a master seed. It plays numTrials trials,
+// split into numProcs chunks of about the same size with the last chunk taking any remainder, and returns the
+// Summary of their payoffs. Worker i draws from the stream with seed rng.DeriveSeed(seed, i).
+func Run(trial Trial, numTrials, numProcs int, seed int64) Summary {
+	if numProcs < 1 {
+		numProcs = 1
+	}
+
+	return RunBatch(trial, numTrials, rng.Streams(seed, numProcs))
+}
+
+// RunBatch takes a Trial, a number of trials, and one random stream per worker. It plays numTrials trials split over
+// the workers with workpool.For, and returns the Summary of their payoffs. The streams are left where the trials
+// stopped drawing from them, so calling RunBatch again continues the same experiment.
+func RunBatch(trial Trial, numTrials int, streams []*rand.Rand) Summary {
+	// Reduce merges in the order of the workers rather than the order they finish, so that the result is repeatable
+	return workpool.Reduce(numTrials, len(streams), func(worker, start, end int) Summary {
+		var s Summary
+		for k := start; k < end; k++ {
+			s.Add(trial(streams[worker]))
+		}
+		return s
+	}, func(total, s Summary) Summary {
+		total.Merge(s)
+		return total
+	})
+}
+
+// RunToPrecision takes a Trial, a target standard error, a batch size, a maximum number of trials, a number of
+// processors, and a master seed. It plays batches of batchSize trials over numProcs workers until the standard
+// error of the mean is at most target, or maxTrials trials have been played, and returns the Summary of every payoff
+// along with whether the target was reached. Since the check only happens between batches, the same arguments
+// always stop after the same number of trials.
+func RunToPrecision(trial Trial, target float64, batchSize, maxTrials, numProcs int, seed int64) (Summary, bool) {
+	if batchSize < 1 {
+		panic("Error: batch size must be positive.")
+	}
+	if numProcs < 1 {
+		numProcs = 1
+	}
+
+	streams := rng.Streams(seed, numProcs)
+
+	var total Summary
+	for total.N() < maxTrials {
+		n := batchSize
+		if total.N()+n > maxTrials {
+			n = maxTrials - total.N()
+		}
+
+		total.Merge(RunBatch(trial, n, streams))
+
+		if total.StdErr() <= target {
+			return total, true
+		}
+	}
+
+	return total, false
+}
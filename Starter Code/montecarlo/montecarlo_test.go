@@ -0,0 +1,96 @@
+package montecarlo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// coinFlip wins 1 or loses 1 with equal probability, so its mean is 0 and its variance is 1.
+func coinFlip(r *rand.Rand) float64 {
+	if r.Intn(2) == 0 {
+		return 1
+	}
+	return -1
+}
+
+// TestSummary checks the running statistics against a direct computation.
+func TestSummary(t *testing.T) {
+	values := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+	var s Summary
+	for _, x := range values {
+		s.Add(x)
+	}
+
+	if s.N() != 8 || s.Mean() != 5 {
+		t.Fatalf("got n = %d and mean = %f, want 8 and 5", s.N(), s.Mean())
+	}
+	// the squared deviations sum to 32
+	if math.Abs(s.Variance()-32.0/7.0) > 1e-12 {
+		t.Errorf("variance = %f, want %f", s.Variance(), 32.0/7.0)
+	}
+
+	// merging two halves must give the same statistics as adding every value
+	var first, second Summary
+	for i, x := range values {
+		if i < 3 {
+			first.Add(x)
+		} else {
+			second.Add(x)
+		}
+	}
+	first.Merge(second)
+
+	if first.N() != s.N() || math.Abs(first.Mean()-s.Mean()) > 1e-12 || math.Abs(first.Variance()-s.Variance()) > 1e-12 {
+		t.Errorf("merged summary (%d, %f, %f) differs from direct summary (%d, %f, %f)",
+			first.N(), first.Mean(), first.Variance(), s.N(), s.Mean(), s.Variance())
+	}
+}
+
+// TestRunReproducible checks that the same seed and number of workers give the same result, and that every trial is played.
+func TestRunReproducible(t *testing.T) {
+	for _, numProcs := range []int{1, 3, 8} {
+		s1 := Run(coinFlip, 10001, numProcs, 42)
+		s2 := Run(coinFlip, 10001, numProcs, 42)
+
+		if s1 != s2 {
+			t.Errorf("two runs with %d workers and the same seed differ", numProcs)
+		}
+		if s1.N() != 10001 {
+			t.Errorf("played %d trials with %d workers, want 10001", s1.N(), numProcs)
+		}
+	}
+}
+
+// TestConfidenceInterval checks that the 95% interval of a fair coin contains 0 and has the expected width.
+func TestConfidenceInterval(t *testing.T) {
+	s := Run(coinFlip, 100000, 4, 7)
+	low, high := s.ConfidenceInterval(0.95)
+
+	if low > 0 || high < 0 {
+		t.Errorf("95%% interval [%f, %f] doesn't contain the true mean 0", low, high)
+	}
+
+	want := 2 * 1.959964 / math.Sqrt(100000)
+	if math.Abs((high-low)-want) > 0.001 {
+		t.Errorf("95%% interval has width %f, want about %f", high-low, want)
+	}
+}
+
+// TestRunToPrecision checks that the experiment stops once the target is met, and gives up at the maximum.
+func TestRunToPrecision(t *testing.T) {
+	s, ok := RunToPrecision(coinFlip, 0.01, 1000, 1000000, 4, 3)
+	if !ok || s.StdErr() > 0.01 {
+		t.Errorf("standard error %f after %d trials, want at most 0.01", s.StdErr(), s.N())
+	}
+	// a standard error of 0.01 needs about 10000 flips, so it shouldn't take many more than that
+	if s.N() > 12000 {
+		t.Errorf("played %d trials to reach the target, want about 10000", s.N())
+	}
+
+	s, ok = RunToPrecision(coinFlip, 0.0001, 1000, 5500, 4, 3)
+	if ok || s.N() != 5500 {
+		t.Errorf("got %d trials and ok = %v, want 5500 and false", s.N(), ok)
+	}
+}
@@ -0,0 +1,68 @@
+// Package rng provides reproducible random number streams for simulations that run on several goroutines.
+//
+// The global source of math/rand is shared by every goroutine, so the numbers each worker draws depend on
+// how the workers happen to be scheduled, and a run can't be repeated. Instead, a simulation takes a single
+// master seed, and every worker draws from its own *rand.Rand whose seed is derived from the master seed and
+// the worker's index. Derived seeds are scrambled with the SplitMix64 finalizer, so that neighboring indices
+// give unrelated streams. The same master seed and number of workers always give the same numbers.
+package rng
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// SplitMix takes a 64-bit integer and returns a well-mixed hash of it, using the finalizer of the SplitMix64 generator.
+func SplitMix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// DeriveSeed takes a master seed and an index, and returns the seed of stream number index.
+func DeriveSeed(master int64, index int) int64 {
+	return int64(SplitMix(SplitMix(uint64(master)) ^ uint64(index)))
+}
+
+// DeriveSeedFromKey takes a master seed and a string key, and returns a seed for the stream named by key.
+// This is useful when work is organized by name, such as by the keys of a map, rather than by position.
+func DeriveSeedFromKey(master int64, key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(SplitMix(SplitMix(uint64(master)) ^ h.Sum64()))
+}
+
+// New takes a seed and returns a new random source with that seed. The source is not safe for use by
+// more than one goroutine at a time.
+func New(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// Streams takes a master seed and a number of workers n, and returns n independent random sources,
+// where source i has the seed DeriveSeed(master, i).
+func Streams(master int64, n int) []*rand.Rand {
+	streams := make([]*rand.Rand, n)
+	for i := range streams {
+		streams[i] = New(DeriveSeed(master, i))
+	}
+	return streams
+}
+
+// ParseSeed takes a string from the command line and returns the seed it holds.
+// The string "time" gives a seed from the current time, so that it should be printed if the run is to be repeated.
+func ParseSeed(s string) (int64, error) {
+	if s == "time" {
+		return time.Now().UnixNano(), nil
+	}
+
+	seed, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("seed must be an integer or \"time\", got %q", s)
+	}
+
+	return seed, nil
+}
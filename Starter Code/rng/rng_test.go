@@ -0,0 +1,84 @@
+package rng
+
+import (
+	"math"
+	"testing"
+)
+
+// TestStreamsReproducible checks that the same master seed gives the same streams.
+func TestStreamsReproducible(t *testing.T) {
+	s1 := Streams(42, 4)
+	s2 := Streams(42, 4)
+
+	for i := range s1 {
+		for k := 0; k < 100; k++ {
+			if s1[i].Int63() != s2[i].Int63() {
+				t.Fatalf("stream %d differs between runs with the same seed at draw %d", i, k)
+			}
+		}
+	}
+}
+
+// TestStreamsDistinct checks that different workers and different master seeds get different streams.
+func TestStreamsDistinct(t *testing.T) {
+	seen := make(map[int64]bool)
+
+	for master := int64(0); master < 10; master++ {
+		for i := 0; i < 100; i++ {
+			seed := DeriveSeed(master, i)
+			if seen[seed] {
+				t.Fatalf("seed of stream %d with master %d repeats an earlier seed", i, master)
+			}
+			seen[seed] = true
+		}
+	}
+
+	if DeriveSeedFromKey(1, "a") == DeriveSeedFromKey(1, "b") {
+		t.Errorf("different keys gave the same seed")
+	}
+}
+
+// TestStreamsUncorrelated checks that the first draws of neighboring streams are not correlated.
+func TestStreamsUncorrelated(t *testing.T) {
+	n := 10000
+	streams := Streams(7, n)
+
+	// compare the first draw of each stream with the first draw of the next
+	sumX, sumY, sumXY, sumXX, sumYY := 0.0, 0.0, 0.0, 0.0, 0.0
+	first := make([]float64, n)
+	for i := range streams {
+		first[i] = streams[i].Float64()
+	}
+	for i := 0; i < n-1; i++ {
+		x, y := first[i], first[i+1]
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+		sumYY += y * y
+	}
+
+	m := float64(n - 1)
+	cov := sumXY/m - sumX/m*sumY/m
+	varX := sumXX/m - sumX/m*sumX/m
+	varY := sumYY/m - sumY/m*sumY/m
+	r := cov / math.Sqrt(varX*varY)
+
+	// the correlation of independent uniforms over 10000 pairs has standard deviation about 0.01
+	if r > 0.05 || r < -0.05 {
+		t.Errorf("neighboring streams are correlated: r = %f", r)
+	}
+}
+
+// TestParseSeed checks parsing of seeds from the command line.
+func TestParseSeed(t *testing.T) {
+	if seed, err := ParseSeed("123"); err != nil || seed != 123 {
+		t.Errorf("ParseSeed(\"123\") = %d, %v", seed, err)
+	}
+	if _, err := ParseSeed("time"); err != nil {
+		t.Errorf("ParseSeed(\"time\") gave error %v", err)
+	}
+	if _, err := ParseSeed("abc"); err == nil {
+		t.Errorf("ParseSeed(\"abc\") gave no error")
+	}
+}
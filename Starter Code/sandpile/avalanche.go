@@ -0,0 +1,168 @@
+package main
+
+import (
+	"math"
+	"rng"
+)
+
+// Avalanche records the response of a stable sandpile to a single added grain.
+type Avalanche struct {
+	size     int     // total number of topples
+	area     int     // number of distinct cells that toppled at least once
+	duration int     // number of waves of topples needed to become stable again
+	extent   float64 // largest distance from the site of the added grain to a cell that toppled
+}
+
+// DriveSandpile is a Board method that takes a number of grains, a number of transient grains, a placement
+// ("random" to drop each grain on a random cell, or "central" to drop every grain on the central cell), and the seed
+// of the random cells.
+// Starting from the Board, which is relaxed first, it drops numTransient + numGrains grains one at a time,
+// letting the pile become stable after each one, and returns the Avalanche caused by each of the last numGrains grains.
+// The Board is modified in place.
+func (b Board) DriveSandpile(numGrains, numTransient int, placement string, seed int64) []Avalanche {
+	if placement != "random" && placement != "central" {
+		panic("Error: placement must be random or central.")
+	}
+
+	// work with a stable pile from the start
+	b.Relax(b.NumRows()/2, b.NumCols()/2)
+
+	avalanches := make([]Avalanche, 0, numGrains)
+	r := rng.New(seed)
+
+	for k := 0; k < numTransient+numGrains; k++ {
+		row, col := b.NumRows()/2, b.NumCols()/2
+		if placement == "random" {
+			row, col = r.Intn(b.NumRows()), r.Intn(b.NumCols())
+		}
+
+		a := b.AddGrain(row, col)
+
+		if k >= numTransient {
+			avalanches = append(avalanches, a)
+		}
+	}
+
+	return avalanches
+}
+
+// AddGrain is a Board method that takes a row and column index.
+// It adds a single grain to the cell at (row, col) of a stable Board, relaxes the Board in place,
+// and returns the resulting Avalanche.
+func (b Board) AddGrain(row, col int) Avalanche {
+	b[row][col]++
+
+	// only the cell that received the grain can be unstable
+	return b.RelaxFrom([][2]int{{row, col}}, row, col)
+}
+
+// Relax is a Board method that takes the row and column of the site where the Board was last disturbed.
+// It topples the Board in place until it is stable and returns the resulting Avalanche, where distances
+// are measured from (row, col).
+func (b Board) Relax(row, col int) Avalanche {
+	wave := make([][2]int, 0)
+	for i := range b {
+		for j := range b[i] {
+			if b[i][j] >= 4 {
+				wave = append(wave, [2]int{i, j})
+			}
+		}
+	}
+
+	return b.RelaxFrom(wave, row, col)
+}
+
+// RelaxFrom is a Board method that takes a slice of the cells that may be unstable, along with the row and
+// column of the site where the Board was disturbed. It topples the Board in place until it is stable and returns
+// the resulting Avalanche, where distances are measured from (row, col).
+// Toppling proceeds in waves: every unstable cell of the current wave topples once, and the cells left unstable
+// by that wave make up the next wave. Only cells that can have changed are examined, so small avalanches are cheap.
+func (b Board) RelaxFrom(wave [][2]int, row, col int) Avalanche {
+	var a Avalanche
+
+	// toppled marks cells that have toppled at least once
+	toppled := make(map[[2]int]bool)
+
+	for len(wave) > 0 {
+		nextWave := make([][2]int, 0)
+		queued := make(map[[2]int]bool) // cells already in the next wave
+		toppledThisWave := false
+
+		for _, cell := range wave {
+			i, j := cell[0], cell[1]
+			if b[i][j] < 4 {
+				continue
+			}
+
+			b[i][j] -= 4
+			a.size++
+			toppledThisWave = true
+
+			if !toppled[cell] {
+				toppled[cell] = true
+				a.area++
+				dist := math.Hypot(float64(i-row), float64(j-col))
+				a.extent = math.Max(a.extent, dist)
+			}
+
+			neighbors := [4][2]int{{i - 1, j}, {i + 1, j}, {i, j - 1}, {i, j + 1}}
+			for _, next := range neighbors {
+				if b.InField(next[0], next[1]) {
+					b[next[0]][next[1]]++
+				}
+			}
+
+			// the cell itself may still be unstable, and so may its neighbors
+			for _, next := range [5][2]int{cell, neighbors[0], neighbors[1], neighbors[2], neighbors[3]} {
+				if b.InField(next[0], next[1]) && b[next[0]][next[1]] >= 4 && !queued[next] {
+					queued[next] = true
+					nextWave = append(nextWave, next)
+				}
+			}
+		}
+
+		if toppledThisWave {
+			a.duration++
+		}
+
+		wave = nextWave
+	}
+
+	return a
+}
+
+// AvalancheSizes takes a slice of Avalanche objects and returns their sizes as floats.
+func AvalancheSizes(avalanches []Avalanche) []float64 {
+	values := make([]float64, len(avalanches))
+	for i, a := range avalanches {
+		values[i] = float64(a.size)
+	}
+	return values
+}
+
+// AvalancheAreas takes a slice of Avalanche objects and returns their areas as floats.
+func AvalancheAreas(avalanches []Avalanche) []float64 {
+	values := make([]float64, len(avalanches))
+	for i, a := range avalanches {
+		values[i] = float64(a.area)
+	}
+	return values
+}
+
+// AvalancheDurations takes a slice of Avalanche objects and returns their durations as floats.
+func AvalancheDurations(avalanches []Avalanche) []float64 {
+	values := make([]float64, len(avalanches))
+	for i, a := range avalanches {
+		values[i] = float64(a.duration)
+	}
+	return values
+}
+
+// AvalancheExtents takes a slice of Avalanche objects and returns their linear extents.
+func AvalancheExtents(avalanches []Avalanche) []float64 {
+	values := make([]float64, len(avalanches))
+	for i, a := range avalanches {
+		values[i] = a.extent
+	}
+	return values
+}
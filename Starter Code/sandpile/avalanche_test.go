@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestAddGrain tests the Avalanches caused by adding a grain to a cell that stays stable and to the single cell of a
+// 1 x 1 board, which topples once and loses all four grains off the edge.
+func TestAddGrain(t *testing.T) {
+	b := InitializeBoard(5)
+	b[2][2] = 2
+	if a := b.AddGrain(2, 2); a != (Avalanche{}) || b[2][2] != 3 {
+		t.Errorf("adding a grain to 2 grains gave %+v leaving %d grains, want no avalanche and 3 grains", a, b[2][2])
+	}
+
+	single := InitializeBoard(1)
+	single[0][0] = 3
+	a := single.AddGrain(0, 0)
+	if a.size != 1 || a.area != 1 || a.duration != 1 || a.extent != 0 {
+		t.Errorf("adding a grain to a 1 x 1 board with 3 grains gave %+v, want size, area and duration 1 with extent 0", a)
+	}
+	if single[0][0] != 0 {
+		t.Errorf("1 x 1 board holds %d grains after toppling, want 0", single[0][0])
+	}
+}
+
+// TestRelaxFrom tests that relaxing a board leaves it stable, and that every grain is either still on the board or
+// was sent off the edge by a topple of a cell on the edge.
+func TestRelaxFrom(t *testing.T) {
+	b := InitializeCentralPile(11, 300)
+	a := b.Relax(5, 5)
+
+	if !b.IsConverged() {
+		t.Fatalf("board is not stable after relaxing")
+	}
+	if a.size == 0 || a.area > 11*11 || a.duration > a.size {
+		t.Errorf("relaxing a pile of 300 grains gave %+v", a)
+	}
+	if b.TotalGrains() > 300 {
+		t.Errorf("board holds %d grains after relaxing 300", b.TotalGrains())
+	}
+}
+
+// TestDriveSandpileSeed tests that the same seed gives the same sequence of avalanches and a different seed gives
+// a different one.
+func TestDriveSandpileSeed(t *testing.T) {
+	first := InitializeBoard(15).DriveSandpile(500, 1000, "random", 4)
+	second := InitializeBoard(15).DriveSandpile(500, 1000, "random", 4)
+	other := InitializeBoard(15).DriveSandpile(500, 1000, "random", 5)
+
+	if len(first) != 500 || len(second) != 500 {
+		t.Fatalf("got %d and %d avalanches, want 500", len(first), len(second))
+	}
+
+	differs := false
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("avalanche %d is %+v and %+v for the same seed", i, first[i], second[i])
+		}
+		if first[i] != other[i] {
+			differs = true
+		}
+	}
+	if !differs {
+		t.Errorf("seeds 4 and 5 gave the same avalanches")
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"stencil"
+	"workpool"
+)
+
+// this file contains a toppling engine for large piles. Rather than rescanning the whole board
+// every step, it keeps an active set of the cells that may be unstable, and a cell with v grains
+// topples v / 4 times at once. Because the sandpile model is abelian, the order of topples
+// does not matter, and the stable configuration reached is the same as with Topple.
+
+// Strip is a block of consecutive rows of a Board that is toppled by a single worker.
+// Its active set is stored row by row as a span of columns that may hold unstable cells.
+// Grains that a worker sends to rows outside its strip are collected in the outboxes up and down
+// rather than written to the board, since those rows belong to other workers.
+type Strip struct {
+	startRow, endRow int   // the strip covers rows startRow up to but not including endRow
+	lo, hi           []int // row startRow + k may be unstable in columns lo[k] through hi[k]; empty if lo[k] > hi[k]
+	active           bool  // true if any row of the strip has a nonempty span
+	up, down         []int // grains sent to row startRow - 1 and to row endRow, by column
+}
+
+// StabilizeBulk is a Board method that returns the stable Board reached by toppling a copy of the Board,
+// using a single worker. The input Board is not changed.
+func (b Board) StabilizeBulk() Board {
+	return b.StabilizeBulkParallel(1)
+}
+
+// StabilizeBulkParallel is a Board method that takes a number of processors.
+// It returns the stable Board reached by toppling a copy of the Board, split into numProcs horizontal strips.
+// Workers topple their own strips in rounds. Within a round, a worker topples until its strip is stable while
+// collecting the grains that leave through the top and bottom of the strip. Between rounds, those grains are
+// delivered to the neighboring strips. We are done once a round ends without any grains crossing a strip boundary.
+// The input Board is not changed.
+func (b Board) StabilizeBulkParallel(numProcs int) Board {
+	board := b.CopyBoard()
+	strips := board.MakeStrips(numProcs)
+
+	// in the first round, any cell may be unstable
+	for _, s := range strips {
+		for _, cell := range board.UnstableCells(s.startRow, s.endRow) {
+			s.Activate(cell[0], cell[1])
+		}
+	}
+
+	for {
+		workpool.Do(len(strips), func(p int) {
+			board.ToppleStrip(strips[p])
+		})
+
+		// deliver grains across strip boundaries, activating the cells that received them
+		exchanged := false
+
+		for p, s := range strips {
+			for col := range s.up {
+				if s.up[col] > 0 {
+					board[s.startRow-1][col] += s.up[col]
+					strips[p-1].Activate(s.startRow-1, col)
+					s.up[col] = 0
+					exchanged = true
+				}
+				if s.down[col] > 0 {
+					board[s.endRow][col] += s.down[col]
+					strips[p+1].Activate(s.endRow, col)
+					s.down[col] = 0
+					exchanged = true
+				}
+			}
+		}
+
+		if !exchanged {
+			break
+		}
+	}
+
+	return board
+}
+
+// MakeStrips is a Board method that takes a number of processors.
+// It returns a slice of pointers to Strip objects with empty active sets, splitting the rows of the Board
+// as evenly as possible over at most numProcs strips.
+func (b Board) MakeStrips(numProcs int) []*Strip {
+	if numProcs < 1 {
+		panic("Error: nonpositive number of processors given to MakeStrips.")
+	}
+	if numProcs > b.NumRows() {
+		numProcs = b.NumRows()
+	}
+
+	strips := make([]*Strip, numProcs)
+
+	for p := range strips {
+		var s Strip
+		s.startRow, s.endRow = stencil.RowBlock(b.NumRows(), numProcs, p)
+		s.lo = make([]int, s.endRow-s.startRow)
+		s.hi = make([]int, s.endRow-s.startRow)
+		for k := range s.lo {
+			s.lo[k] = b.NumCols()
+			s.hi[k] = -1
+		}
+		s.up = make([]int, b.NumCols())
+		s.down = make([]int, b.NumCols())
+		strips[p] = &s
+	}
+
+	return strips
+}
+
+// Activate is a Strip method that takes the row and column of a cell in the strip and adds it to the active set.
+func (s *Strip) Activate(row, col int) {
+	k := row - s.startRow
+	if col < s.lo[k] {
+		s.lo[k] = col
+	}
+	if col > s.hi[k] {
+		s.hi[k] = col
+	}
+	s.active = true
+}
+
+// UnstableCells is a Board method that takes a range of rows.
+// It returns the coordinates of every cell in rows startRow up to but not including endRow with at least four grains.
+func (b Board) UnstableCells(startRow, endRow int) [][2]int {
+	cells := make([][2]int, 0)
+	for i := startRow; i < endRow; i++ {
+		for j := range b[i] {
+			if b[i][j] >= 4 {
+				cells = append(cells, [2]int{i, j})
+			}
+		}
+	}
+	return cells
+}
+
+// ToppleStrip is a Board method that takes a pointer to a Strip.
+// It topples the rows of the strip until they are stable, only ever writing to those rows.
+// Grains leaving the strip are added to its outboxes, and grains leaving the board are lost.
+// Each pass sweeps down the strip, visiting only the active span of each row, and a cell that
+// receives grains is added to the active set for a later visit.
+func (b Board) ToppleStrip(s *Strip) {
+	numRows := b.NumRows()
+	numCols := b.NumCols()
+
+	for s.active {
+		s.active = false
+
+		for i := s.startRow; i < s.endRow; i++ {
+			k := i - s.startRow
+			lo, hi := s.lo[k], s.hi[k]
+			if lo > hi {
+				continue
+			}
+			s.lo[k], s.hi[k] = numCols, -1
+
+			row := b[i]
+			for j := lo; j <= hi; j++ {
+				if row[j] < 4 {
+					continue
+				}
+
+				// topple as many times as we can in one go
+				q := row[j] / 4
+				row[j] -= 4 * q
+
+				if j > 0 {
+					row[j-1] += q
+					s.Activate(i, j-1)
+				}
+				if j < numCols-1 {
+					row[j+1] += q
+					s.Activate(i, j+1)
+				}
+				if i > 0 {
+					if i-1 < s.startRow {
+						s.up[j] += q
+					} else {
+						b[i-1][j] += q
+						s.Activate(i-1, j)
+					}
+				}
+				if i < numRows-1 {
+					if i+1 >= s.endRow {
+						s.down[j] += q
+					} else {
+						b[i+1][j] += q
+						s.Activate(i+1, j)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// NaiveStabilize returns the stable Board reached by repeatedly toppling every unstable cell of b at once.
+func NaiveStabilize(b Board) Board {
+	for !b.IsConverged() {
+		b = b.Topple()
+	}
+	return b
+}
+
+// TestBulkMatchesNaive tests that the bulk engine reaches exactly the same stable Board as the naive engine,
+// for central and random piles and for several numbers of processors.
+func TestBulkMatchesNaive(t *testing.T) {
+	boards := map[string]Board{
+		"central": InitializeCentralPile(31, 5000),
+		"random":  InitializeRandomPiles(24, 8000, 1),
+		"single":  InitializeCentralPile(1, 17),
+	}
+
+	for name, b := range boards {
+		want := NaiveStabilize(b)
+
+		for _, numProcs := range []int{1, 2, 3, 7, 64} {
+			got := b.StabilizeBulkParallel(numProcs)
+			if !got.Equals(want) {
+				t.Errorf("%s pile with %d procs: bulk and naive engines disagree", name, numProcs)
+			}
+		}
+	}
+}
+
+// BenchmarkNaive measures the naive engine on a central pile.
+func BenchmarkNaive(b *testing.B) {
+	board := InitializeCentralPile(101, 20000)
+	for i := 0; i < b.N; i++ {
+		NaiveStabilize(board)
+	}
+}
+
+// BenchmarkBulk measures the bulk engine on the same central pile as BenchmarkNaive, on the same pile
+// in a much larger board where most cells never topple, and on a larger pile, for increasing numbers of processors.
+// Piles of a million grains or more take minutes, so pass -benchtime 1x when adding them here.
+func BenchmarkBulk(b *testing.B) {
+	piles := []struct {
+		size, pile int
+	}{{101, 20000}, {1001, 20000}, {401, 100000}}
+
+	for _, p := range piles {
+		board := InitializeCentralPile(p.size, p.pile)
+		for _, numProcs := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("size=%d,pile=%d,procs=%d", p.size, p.pile, numProcs), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					board.StabilizeBulkParallel(numProcs)
+				}
+			})
+		}
+	}
+}
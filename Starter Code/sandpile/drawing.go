@@ -1,11 +1,38 @@
 package main
 
 import (
-	"canvas"
 	"image"
+	"image/color"
 	"math"
+	"workpool"
 )
 
+// Palette gives the colors of cells holding 0, 1, 2, and 3 grains.
+// Unstable cells holding more grains are colored by OverflowColor.
+type Palette [4]color.RGBA
+
+// PaletteByName takes the name of a palette ("gray", "ocean", or "fire") and returns that Palette.
+func PaletteByName(name string) Palette {
+	switch name {
+	case "gray":
+		return Palette{{30, 30, 30, 255}, {95, 95, 95, 255}, {190, 190, 190, 255}, {255, 255, 255, 255}}
+	case "ocean":
+		return Palette{{10, 10, 40, 255}, {40, 90, 200, 255}, {120, 190, 250, 255}, {250, 250, 250, 255}}
+	case "fire":
+		return Palette{{20, 0, 0, 255}, {150, 30, 30, 255}, {240, 140, 20, 255}, {255, 240, 150, 255}}
+	}
+
+	panic("Error: unknown palette " + name + ".")
+}
+
+// OverflowColor takes a number of grains val greater than 3 and returns a color that runs from white
+// toward red as val grows.
+func OverflowColor(val int) color.RGBA {
+	green := uint8(255 - math.Min(255, 40*math.Log2(float64(val-3))))
+	blue := uint8(255 - math.Min(255, 80*math.Log2(float64(val-3))))
+	return color.RGBA{255, green, blue, 255}
+}
+
 // AnimateBoards takes a slice of Board objects along with a cell width parameter.
 // It generates a slice of images corresponding to drawing each Board on a canvas with the given cell width.
 func AnimateBoards(timePoints []Board, cellWidth int) []image.Image {
@@ -26,62 +53,80 @@ func AnimateBoards(timePoints []Board, cellWidth int) []image.Image {
 // DrawToImage is a Board method.
 // Input: an integer cellWidth
 // Output: the image.Image object corresponding to drawing the board
-// on a square canvas, where each cell has width cellWidth.
+// on a square canvas, where each cell has width cellWidth, using the gray palette.
 func (b Board) DrawToImage(cellWidth int) image.Image {
+	return b.DrawToImageWithPalette(cellWidth, PaletteByName("gray"))
+}
+
+// DrawToImageWithPalette is a Board method.
+// Input: an integer cellWidth and a Palette
+// Output: the image.Image object corresponding to drawing the board, where each cell is a
+// cellWidth x cellWidth square colored according to its number of grains.
+// Pixels are written directly rather than through a canvas, so even very large boards are quick to draw;
+// a cellWidth of 1 draws the board at full resolution, one pixel per cell.
+func (b Board) DrawToImageWithPalette(cellWidth int, p Palette) image.Image {
 	if b == nil {
 		panic("Can't Draw a nil board.")
 	}
-	numRows := len(b)
-	canvasWidth := numRows * cellWidth
-
-	// create a new square canvas
-	c := canvas.CreateNewCanvas(canvasWidth, canvasWidth)
-
-	darkGray := canvas.MakeColor(30, 30, 30)
-	gray := canvas.MakeColor(95, 95, 95)
-	lightGray := canvas.MakeColor(190, 190, 190)
-	white := canvas.MakeColor(255, 255, 255)
+	if cellWidth <= 0 {
+		panic("Error: nonpositive cellWidth given to DrawToImageWithPalette.")
+	}
 
-	// create a black background
-	c.SetFillColor(darkGray)
-	c.ClearRect(0, 0, canvasWidth, canvasWidth)
-	c.Fill()
+	width := b.NumCols() * cellWidth
+	height := b.NumRows() * cellWidth
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
 
-	// range over all the bodies and draw them.
 	for i := range b {
-		for j := range b[i] {
-			val := b[i][j]
-
-			if val == 0 {
-				c.SetFillColor(darkGray)
-			} else if val == 1 {
-				c.SetFillColor(gray)
-			} else if val == 2 {
-				c.SetFillColor(lightGray)
-			} else if val == 3 {
-				c.SetFillColor(white)
+		// draw the first pixel row of this row of cells, then copy it down cellWidth - 1 times
+		rowStart := img.PixOffset(0, i*cellWidth)
+		pixRow := img.Pix[rowStart : rowStart+4*width]
+
+		for j, val := range b[i] {
+			var c color.RGBA
+			if val >= 0 && val <= 3 {
+				c = p[val]
 			} else {
-				c.SetFillColor(canvas.MakeColor(255, uint8(255-math.Min(255, 40*math.Log2(float64(val-3)))), uint8(255-math.Min(255, 80*math.Log2(float64(val-3))))))
+				c = OverflowColor(val)
 			}
 
-			// set central coordinates
-			x := float64(j*cellWidth) + float64(cellWidth)/2
-			y := float64(i*cellWidth) + float64(cellWidth)/2
-
-			scalingFactor := 0.8 // to make circle smaller
-
-			if val > 0 {
-				c.Circle(x, y, scalingFactor*float64(cellWidth)/2)
-				c.Fill()
+			for x := j * cellWidth; x < (j+1)*cellWidth; x++ {
+				pixRow[4*x] = c.R
+				pixRow[4*x+1] = c.G
+				pixRow[4*x+2] = c.B
+				pixRow[4*x+3] = c.A
 			}
 		}
+
+		for y := 1; y < cellWidth; y++ {
+			offset := img.PixOffset(0, i*cellWidth+y)
+			copy(img.Pix[offset:offset+4*width], pixRow)
+		}
 	}
-	// we want to return an image!
-	//c.SaveToPNG("sandpile.png")
-	return c.GetImage()
+
+	return img
 }
 
-// AnimateBoardsParallel takes a slice of Board objects along with a cell width parameter.
-// It generates a slice of images by drawing each Board on a canvas with the given cell width, using parallel processing.
+// AnimateBoardsParallel takes a slice of Board objects along with a cell width parameter, a Palette, and a number of processors.
+// It generates a slice of images by drawing each Board with the given cell width and Palette, using parallel processing.
+// The Boards are split into numProcs blocks of consecutive frames, and each block is drawn by its own goroutine.
+func AnimateBoardsParallel(timePoints []Board, cellWidth int, p Palette, numProcs int) []image.Image {
+	images := make([]image.Image, len(timePoints))
+
+	if len(timePoints) == 0 {
+		panic("Error: no Board objects present in input to AnimateBoardsParallel.")
+	}
+	// each processor draws about the same number of boards, and the last one draws any remainder
+	workpool.For(len(timePoints), numProcs, func(worker, startIndex, endIndex int) {
+		AnimateBoardsOneProc(timePoints[startIndex:endIndex], images[startIndex:endIndex], cellWidth, p)
+	})
 
-// YOUR PARALLEL CODE GOES HERE
+	return images
+}
+
+// AnimateBoardsOneProc takes a slice of Boards, a slice of images of the same length, a cell width, and a Palette.
+// It draws each Board into the corresponding entry of images.
+func AnimateBoardsOneProc(timePoints []Board, images []image.Image, cellWidth int, p Palette) {
+	for i := range timePoints {
+		images[i] = timePoints[i].DrawToImageWithPalette(cellWidth, p)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"testing"
+)
+
+// TestAnimateBoardsParallel tests that drawing frames in parallel gives the same images as drawing them one at a time
+// with the same Palette, for several numbers of processors.
+func TestAnimateBoardsParallel(t *testing.T) {
+	boards := SimulateSandpiles(InitializeCentralPile(9, 200))
+	p := PaletteByName("fire")
+
+	for _, numProcs := range []int{1, 2, 3, 64} {
+		images := AnimateBoardsParallel(boards, 2, p, numProcs)
+		if len(images) != len(boards) {
+			t.Fatalf("%d processors: got %d images for %d boards", numProcs, len(images), len(boards))
+		}
+
+		for i, img := range images {
+			want := boards[i].DrawToImageWithPalette(2, p).(*image.RGBA)
+			if !bytes.Equal(img.(*image.RGBA).Pix, want.Pix) {
+				t.Fatalf("%d processors: frame %d differs from drawing it serially", numProcs, i)
+			}
+		}
+	}
+
+	// the palette must be the one passed in, not the default gray
+	stable := boards[len(boards)-1]
+	fire := AnimateBoardsParallel([]Board{stable}, 1, p, 2)[0].(*image.RGBA)
+	gray := stable.DrawToImage(1).(*image.RGBA)
+	if bytes.Equal(fire.Pix, gray.Pix) {
+		t.Errorf("AnimateBoardsParallel drew with the gray palette instead of the one given")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"rng"
+)
+
+// this file contains functions shared by the serial and parallel versions of our code.
+
+// InitializeBoard takes an integer size and returns a size x size Board with no grains.
+func InitializeBoard(size int) Board {
+	if size <= 0 {
+		panic("Error: nonpositive size given to InitializeBoard.")
+	}
+
+	b := make(Board, size)
+	for i := range b {
+		b[i] = make([]int, size)
+	}
+
+	return b
+}
+
+// InitializeCentralPile takes a size and a number of grains pile.
+// It returns a size x size Board in which all pile grains are placed on the central cell.
+func InitializeCentralPile(size, pile int) Board {
+	b := InitializeBoard(size)
+	b[size/2][size/2] = pile
+	return b
+}
+
+// InitializeRandomPiles takes a size, a number of grains pile, and a seed.
+// It returns a size x size Board in which each of the pile grains is placed on a cell chosen uniformly at random,
+// using random numbers drawn from the seed.
+func InitializeRandomPiles(size, pile int, seed int64) Board {
+	b := InitializeBoard(size)
+	r := rng.New(seed)
+
+	for k := 0; k < pile; k++ {
+		b[r.Intn(size)][r.Intn(size)]++
+	}
+
+	return b
+}
+
+// InitializePile takes a size, a number of grains pile, a placement ("central" or "random"), and the seed of a random placement.
+// It returns a size x size Board with the grains placed by InitializeCentralPile or InitializeRandomPiles.
+func InitializePile(size, pile int, placement string, seed int64) Board {
+	if placement == "central" {
+		return InitializeCentralPile(size, pile)
+	} else if placement == "random" {
+		return InitializeRandomPiles(size, pile, seed)
+	}
+
+	panic("Error: placement must be central or random.")
+}
+
+// NumRows is a Board method that returns the number of rows in the Board.
+func (b Board) NumRows() int {
+	return len(b)
+}
+
+// NumCols is a Board method that returns the number of columns in the Board.
+func (b Board) NumCols() int {
+	if b.NumRows() == 0 {
+		panic("Error: empty board given to NumCols.")
+	}
+	return len(b[0])
+}
+
+// InField is a Board method that takes a row and column index.
+// It returns true if (row, col) is a cell of the Board and false otherwise.
+func (b Board) InField(row, col int) bool {
+	return row >= 0 && row < b.NumRows() && col >= 0 && col < b.NumCols()
+}
+
+// IsConverged is a Board method that returns true if every cell has fewer than four grains,
+// so that no cell can topple.
+func (b Board) IsConverged() bool {
+	for i := range b {
+		for j := range b[i] {
+			if b[i][j] >= 4 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CopyBoard is a Board method that returns a deep copy of the Board.
+func (b Board) CopyBoard() Board {
+	newBoard := make(Board, len(b))
+	for i := range b {
+		newBoard[i] = make([]int, len(b[i]))
+		copy(newBoard[i], b[i])
+	}
+	return newBoard
+}
+
+// Equals is a Board method that takes another Board b2 and returns true if the two Boards
+// have the same dimensions and the same number of grains in every cell.
+func (b Board) Equals(b2 Board) bool {
+	if len(b) != len(b2) {
+		return false
+	}
+	for i := range b {
+		if len(b[i]) != len(b2[i]) {
+			return false
+		}
+		for j := range b[i] {
+			if b[i][j] != b2[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TotalGrains is a Board method that returns the total number of grains on the Board.
+func (b Board) TotalGrains() int {
+	total := 0
+	for i := range b {
+		for j := range b[i] {
+			total += b[i][j]
+		}
+	}
+	return total
+}
@@ -0,0 +1,119 @@
+package main
+
+// this file contains the operations of the sandpile group, whose elements are the recurrent
+// stable configurations of a board and whose operation is addition followed by stabilization.
+
+// Stabilize is a Board method that returns a new Board resulting from toppling a copy of the Board until it is stable.
+// The input Board is not changed.
+func (b Board) Stabilize() Board {
+	return b.StabilizeBulk()
+}
+
+// AddBoards takes two Boards of the same dimensions.
+// It returns the stabilization of their cellwise sum.
+func AddBoards(b1, b2 Board) Board {
+	if b1.NumRows() != b2.NumRows() || b1.NumCols() != b2.NumCols() {
+		panic("Error: boards given to AddBoards have different dimensions.")
+	}
+
+	sum := ConstantBoard(b1.NumRows(), b1.NumCols(), 0)
+	for i := range sum {
+		for j := range sum[i] {
+			sum[i][j] = b1[i][j] + b2[i][j]
+		}
+	}
+
+	return sum.Stabilize()
+}
+
+// ConstantBoard takes a number of rows and columns along with a value.
+// It returns a numRows x numCols Board in which every cell holds val grains.
+func ConstantBoard(numRows, numCols, val int) Board {
+	b := make(Board, numRows)
+	for i := range b {
+		b[i] = make([]int, numCols)
+		for j := range b[i] {
+			b[i][j] = val
+		}
+	}
+	return b
+}
+
+// IdentityBoard takes a number of rows and columns.
+// It returns the identity element of the sandpile group of a numRows x numCols grid,
+// computed as the stabilization of 6 - S(6), where 6 is the board with six grains in every cell
+// and S(6) is its stabilization.
+func IdentityBoard(numRows, numCols int) Board {
+	six := ConstantBoard(numRows, numCols, 6)
+	stableSix := six.Stabilize()
+
+	difference := ConstantBoard(numRows, numCols, 0)
+	for i := range difference {
+		for j := range difference[i] {
+			difference[i][j] = six[i][j] - stableSix[i][j]
+		}
+	}
+
+	return difference.Stabilize()
+}
+
+// InverseBoard takes a recurrent Board and returns its inverse in the sandpile group, the recurrent Board
+// that gives the identity when added to it. Writing 6 - S(6) as in IdentityBoard, which is equivalent to no grains
+// at all and holds at least three grains in every cell, the inverse is the stabilization of 6 - S(6) - b
+// plus the identity.
+func InverseBoard(b Board) Board {
+	six := ConstantBoard(b.NumRows(), b.NumCols(), 6)
+	stableSix := six.Stabilize()
+
+	difference := ConstantBoard(b.NumRows(), b.NumCols(), 0)
+	for i := range difference {
+		for j := range difference[i] {
+			difference[i][j] = six[i][j] - stableSix[i][j] - b[i][j]
+		}
+	}
+
+	return AddBoards(difference, IdentityBoard(b.NumRows(), b.NumCols()))
+}
+
+// BurningBoard takes a number of rows and columns.
+// It returns the numRows x numCols Board in which every cell holds one grain for each of its sides on the edge
+// of the grid, so that corner cells hold two grains, other edge cells hold one, and interior cells hold none.
+func BurningBoard(numRows, numCols int) Board {
+	b := ConstantBoard(numRows, numCols, 0)
+	for i := range b {
+		for j := range b[i] {
+			if i == 0 {
+				b[i][j]++
+			}
+			if i == numRows-1 {
+				b[i][j]++
+			}
+			if j == 0 {
+				b[i][j]++
+			}
+			if j == numCols-1 {
+				b[i][j]++
+			}
+		}
+	}
+	return b
+}
+
+// IsRecurrent is a Board method that returns true if the Board is a recurrent configuration, that is,
+// an element of the sandpile group. It uses Dhar's burning test: a stable Board is recurrent exactly when
+// adding the burning board and stabilizing gives back the same Board.
+func (b Board) IsRecurrent() bool {
+	if !b.IsConverged() {
+		return false
+	}
+
+	for i := range b {
+		for j := range b[i] {
+			if b[i][j] < 0 {
+				return false
+			}
+		}
+	}
+
+	return AddBoards(b, BurningBoard(b.NumRows(), b.NumCols())).Equals(b)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math/rand"
+	"rng"
+	"testing"
+)
+
+// RandomRecurrentBoard takes a number of rows and columns and a random stream, and returns a random recurrent Board,
+// obtained by adding random grains to the identity and stabilizing.
+func RandomRecurrentBoard(numRows, numCols int, r *rand.Rand) Board {
+	b := ConstantBoard(numRows, numCols, 0)
+	for i := range b {
+		for j := range b[i] {
+			b[i][j] = r.Intn(4)
+		}
+	}
+	return AddBoards(b, IdentityBoard(numRows, numCols))
+}
+
+// TestIdentity tests that IdentityBoard is recurrent and is its own sum, on several small grids.
+func TestIdentity(t *testing.T) {
+	for _, dims := range [][2]int{{1, 1}, {2, 2}, {3, 3}, {3, 5}, {8, 8}} {
+		e := IdentityBoard(dims[0], dims[1])
+
+		if !e.IsRecurrent() {
+			t.Errorf("IdentityBoard(%d, %d) = %v is not recurrent", dims[0], dims[1], e)
+		}
+
+		if !AddBoards(e, e).Equals(e) {
+			t.Errorf("IdentityBoard(%d, %d) = %v is not idempotent", dims[0], dims[1], e)
+		}
+	}
+
+	// the identity of the 2 x 2 grid is known to be all twos
+	if !IdentityBoard(2, 2).Equals(ConstantBoard(2, 2, 2)) {
+		t.Errorf("IdentityBoard(2, 2) = %v, want all twos", IdentityBoard(2, 2))
+	}
+}
+
+// TestGroupAxioms tests the identity, closure, commutativity, associativity, and inverses of AddBoards
+// on random recurrent Boards.
+func TestGroupAxioms(t *testing.T) {
+	r := rng.New(1)
+
+	for trial := 0; trial < 20; trial++ {
+		numRows, numCols := 1+r.Intn(6), 1+r.Intn(6)
+		e := IdentityBoard(numRows, numCols)
+		a := RandomRecurrentBoard(numRows, numCols, r)
+		b := RandomRecurrentBoard(numRows, numCols, r)
+		c := RandomRecurrentBoard(numRows, numCols, r)
+
+		if !AddBoards(a, e).Equals(a) {
+			t.Errorf("%v + identity = %v", a, AddBoards(a, e))
+		}
+
+		if !AddBoards(a, b).IsRecurrent() {
+			t.Errorf("%v + %v is not recurrent", a, b)
+		}
+
+		if !AddBoards(a, b).Equals(AddBoards(b, a)) {
+			t.Errorf("addition of %v and %v is not commutative", a, b)
+		}
+
+		if !AddBoards(AddBoards(a, b), c).Equals(AddBoards(a, AddBoards(b, c))) {
+			t.Errorf("addition of %v, %v and %v is not associative", a, b, c)
+		}
+
+		inverse := InverseBoard(a)
+		if !inverse.IsRecurrent() {
+			t.Errorf("inverse %v of %v is not recurrent", inverse, a)
+		}
+		if !AddBoards(a, inverse).Equals(e) {
+			t.Errorf("%v + its inverse %v = %v, want the identity %v", a, inverse, AddBoards(a, inverse), e)
+		}
+	}
+}
+
+// TestIsRecurrent tests IsRecurrent on configurations whose status is known.
+func TestIsRecurrent(t *testing.T) {
+	if !ConstantBoard(4, 6, 3).IsRecurrent() {
+		t.Errorf("the maximal stable board is not recurrent")
+	}
+
+	if ConstantBoard(4, 6, 0).IsRecurrent() {
+		t.Errorf("the empty board is recurrent")
+	}
+
+	if ConstantBoard(3, 3, 4).IsRecurrent() {
+		t.Errorf("an unstable board is recurrent")
+	}
+
+	// two neighboring zeros can never be reached by adding grains to a recurrent board
+	b := ConstantBoard(3, 3, 3)
+	b[1][1], b[1][2] = 0, 0
+	if b.IsRecurrent() {
+		t.Errorf("%v is recurrent", b)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+)
+
+// WriteAvalanchesToFile takes a slice of Avalanche objects and a file name.
+// It writes one line per avalanche to a CSV file.
+func WriteAvalanchesToFile(avalanches []Avalanche, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, "grain,size,area,duration,extent")
+	for i, a := range avalanches {
+		fmt.Fprintf(writer, "%d,%d,%d,%d,%g\n", i, a.size, a.area, a.duration, a.extent)
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// WriteHistogramsToFile takes a map from the name of a quantity to its histogram, the names in the order
+// to write them, and a file name. It writes one line per bin of every histogram to a CSV file.
+func WriteHistogramsToFile(histograms map[string][]Bin, names []string, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, "quantity,low,high,count,density")
+	for _, name := range names {
+		for _, bin := range histograms[name] {
+			fmt.Fprintf(writer, "%s,%g,%g,%d,%g\n", name, bin.low, bin.high, bin.count, bin.density)
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// WriteFitsToFile takes a map from the name of a quantity to its power law fit, the names in the order
+// to write them, and a file name. It writes one line per fit to a CSV file, skipping names without a fit.
+func WriteFitsToFile(fits map[string]PowerLawFit, names []string, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	fmt.Fprintln(writer, "quantity,alpha,stdErr,xmin,n,ks")
+	for _, name := range names {
+		fit, ok := fits[name]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(writer, "%s,%g,%g,%g,%d,%g\n", name, fit.alpha, fit.stdErr, fit.xmin, fit.n, fit.ks)
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SaveImageToPNG takes an image and a file name and writes the image to a PNG file.
+func SaveImageToPNG(img image.Image, filename string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	err = png.Encode(file, img)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -1,12 +1,339 @@
 package main
 
+import (
+	"fmt"
+	"gifhelper"
+	"os"
+	"rng"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Run the program
 func main() {
 
+	// "driven" as the first CLA drives a stable pile one grain at a time and records avalanches
+	if len(os.Args) > 1 && os.Args[1] == "driven" {
+		RunDriven()
+		return
+	}
+
+	// "bulk" as the first CLA times only the bulk engine, which can stabilize piles far too large
+	// for the engines that keep every intermediate board
+	if len(os.Args) > 1 && os.Args[1] == "bulk" {
+		RunBulk()
+		return
+	}
+
+	// "animate" as the first CLA draws every few steps of the parallel simulation to a GIF
+	if len(os.Args) > 1 && os.Args[1] == "animate" {
+		RunAnimate()
+		return
+	}
+
+	// "identity" as the first CLA draws the identity element of the sandpile group
+	if len(os.Args) > 1 && os.Args[1] == "identity" {
+		RunIdentity()
+		return
+	}
+
 	// parse os.Args here
+	// CLAs: size of the board, number of grains, placement (as in ParsePlacement), and optionally
+	// a palette ("gray", "ocean", or "fire") for drawing the stable configuration to a PNG
+	if len(os.Args) != 4 && len(os.Args) != 5 {
+		panic("Error: incorrect number of command line arguments.")
+	}
+
+	size, err := strconv.Atoi(os.Args[1])
+	Check(err)
+
+	pile, err := strconv.Atoi(os.Args[2])
+	Check(err)
+
+	placement, seed := ParsePlacement(os.Args[3])
+
+	if size <= 0 || pile < 0 {
+		panic("Error: size must be positive and pile must be nonnegative.")
+	}
+
+	initialBoard := InitializePile(size, pile, placement, seed)
+
+	numProcs := runtime.NumCPU()
 
 	// simulate serially first and time
+	start := time.Now()
+	serialBoards := SimulateSandpiles(initialBoard)
+	elapsed := time.Since(start)
+	fmt.Printf("Toppling serially took %s over %d steps\n", elapsed, len(serialBoards)-1)
 
 	// then, simulate parallel and time
+	start2 := time.Now()
+	parallelBoards := SimulateSandpilesParallel(initialBoard, numProcs)
+	elapsed2 := time.Since(start2)
+	fmt.Printf("Toppling in parallel over %d processors took %s over %d steps\n", numProcs, elapsed2, len(parallelBoards)-1)
+
+	// the sandpile model is abelian, so both versions must reach the same stable configuration
+	serialFinal := serialBoards[len(serialBoards)-1]
+	parallelFinal := parallelBoards[len(parallelBoards)-1]
+
+	if !serialFinal.Equals(parallelFinal) {
+		panic("Error: serial and parallel simulations reached different stable configurations.")
+	}
+
+	fmt.Println("Serial and parallel simulations reached the same stable configuration.")
+
+	// finally, time the bulk engine, which only returns the stable configuration
+	start3 := time.Now()
+	bulkFinal := initialBoard.StabilizeBulk()
+	elapsed3 := time.Since(start3)
+	fmt.Printf("Bulk toppling serially took %s\n", elapsed3)
+
+	start4 := time.Now()
+	bulkParallelFinal := initialBoard.StabilizeBulkParallel(numProcs)
+	elapsed4 := time.Since(start4)
+	fmt.Printf("Bulk toppling in parallel over %d processors took %s\n", numProcs, elapsed4)
+
+	if !bulkFinal.Equals(serialFinal) || !bulkParallelFinal.Equals(serialFinal) {
+		panic("Error: bulk toppling reached a different stable configuration.")
+	}
+
+	fmt.Println("Bulk toppling reached the same stable configuration.")
+
+	// draw only the stable configuration, one pixel per cell
+	if len(os.Args) == 5 {
+		outputFile := "sandpile_" + placement + ".png"
+		SaveImageToPNG(bulkFinal.DrawToImageWithPalette(1, PaletteByName(os.Args[4])), outputFile)
+		fmt.Println("Stable configuration drawn to " + outputFile + ".")
+	}
+}
+
+// RunBulk parses the CLAs of the bulk command and times the serial and parallel bulk engines on a single pile,
+// without running the engines that keep every intermediate board.
+func RunBulk() {
+	// CLAs: bulk, size of the board, number of grains, placement (as in ParsePlacement), and optionally
+	// a palette for drawing the stable configuration to a PNG
+	if len(os.Args) != 5 && len(os.Args) != 6 {
+		panic("Error: incorrect number of command line arguments for bulk.")
+	}
+
+	size, err := strconv.Atoi(os.Args[2])
+	Check(err)
+
+	pile, err := strconv.Atoi(os.Args[3])
+	Check(err)
+
+	placement, seed := ParsePlacement(os.Args[4])
+
+	if size <= 0 || pile < 0 {
+		panic("Error: size must be positive and pile must be nonnegative.")
+	}
+
+	initialBoard := InitializePile(size, pile, placement, seed)
+
+	numProcs := runtime.NumCPU()
+
+	start := time.Now()
+	bulkFinal := initialBoard.StabilizeBulk()
+	elapsed := time.Since(start)
+	fmt.Printf("Bulk toppling serially took %s\n", elapsed)
+
+	start2 := time.Now()
+	bulkParallelFinal := initialBoard.StabilizeBulkParallel(numProcs)
+	elapsed2 := time.Since(start2)
+	fmt.Printf("Bulk toppling in parallel over %d processors took %s\n", numProcs, elapsed2)
+
+	if !bulkFinal.Equals(bulkParallelFinal) {
+		panic("Error: serial and parallel bulk toppling reached different stable configurations.")
+	}
+
+	fmt.Println("Serial and parallel bulk toppling reached the same stable configuration.")
+
+	if len(os.Args) == 6 {
+		outputFile := "sandpile_" + placement + ".png"
+		SaveImageToPNG(bulkFinal.DrawToImageWithPalette(1, PaletteByName(os.Args[5])), outputFile)
+		fmt.Println("Stable configuration drawn to " + outputFile + ".")
+	}
+}
+
+// RunAnimate parses the CLAs of the animate command, topples a single pile in parallel, and draws every
+// imageFrequency-th step, along with the stable configuration, to a GIF.
+func RunAnimate() {
+	// CLAs: animate, size of the board, number of grains, placement (as in ParsePlacement), cell width,
+	// imageFrequency, and a palette
+	if len(os.Args) != 8 {
+		panic("Error: incorrect number of command line arguments for animate.")
+	}
+
+	size, err := strconv.Atoi(os.Args[2])
+	Check(err)
+
+	pile, err := strconv.Atoi(os.Args[3])
+	Check(err)
+
+	placement, seed := ParsePlacement(os.Args[4])
+
+	cellWidth, err := strconv.Atoi(os.Args[5])
+	Check(err)
+
+	imageFrequency, err := strconv.Atoi(os.Args[6])
+	Check(err)
+
+	palette := PaletteByName(os.Args[7])
+
+	if size <= 0 || pile < 0 || cellWidth <= 0 || imageFrequency <= 0 {
+		panic("Error: size, cellWidth and imageFrequency must be positive and pile must be nonnegative.")
+	}
+
+	numProcs := runtime.NumCPU()
+
+	boards := SimulateSandpilesParallel(InitializePile(size, pile, placement, seed), numProcs)
+
+	// keep every imageFrequency-th board, and always the stable one at the end
+	frames := make([]Board, 0, len(boards)/imageFrequency+2)
+	for i := 0; i < len(boards); i += imageFrequency {
+		frames = append(frames, boards[i])
+	}
+	if (len(boards)-1)%imageFrequency != 0 {
+		frames = append(frames, boards[len(boards)-1])
+	}
+
+	fmt.Printf("Toppling took %d steps. Drawing %d frames.\n", len(boards)-1, len(frames))
+
+	images := AnimateBoardsParallel(frames, cellWidth, palette, numProcs)
+
+	outputFile := "sandpile_" + placement
+	gifhelper.ImagesToGIF(images, outputFile)
+
+	fmt.Println("Animation drawn to " + outputFile + ".out.gif.")
+}
+
+// RunDriven parses the CLAs of the driven command, drives a sandpile one grain at a time,
+// and writes the avalanches, their histograms, and power law fits to CSV files.
+func RunDriven() {
+	// CLAs: driven, size of the board, number of recorded grains, number of transient grains,
+	// placement (as in ParsePlacement)
+	if len(os.Args) != 6 {
+		panic("Error: incorrect number of command line arguments for driven.")
+	}
+
+	size, err := strconv.Atoi(os.Args[2])
+	Check(err)
+
+	numGrains, err := strconv.Atoi(os.Args[3])
+	Check(err)
+
+	numTransient, err := strconv.Atoi(os.Args[4])
+	Check(err)
+
+	placement, seed := ParsePlacement(os.Args[5])
+
+	if size <= 0 || numGrains <= 0 || numTransient < 0 {
+		panic("Error: size and number of grains must be positive.")
+	}
+
+	// the transient grains bring the empty pile up to its critical state before we record anything
+	b := InitializeBoard(size)
+
+	start := time.Now()
+	avalanches := b.DriveSandpile(numGrains, numTransient, placement, seed)
+	fmt.Printf("Driving the sandpile with %d grains took %s\n", numTransient+numGrains, time.Since(start))
+
+	names := []string{"size", "area", "duration", "extent"}
+	values := map[string][]float64{
+		"size":     AvalancheSizes(avalanches),
+		"area":     AvalancheAreas(avalanches),
+		"duration": AvalancheDurations(avalanches),
+		"extent":   AvalancheExtents(avalanches),
+	}
+
+	histograms := make(map[string][]Bin)
+	fits := make(map[string]PowerLawFit)
+
+	for _, name := range names {
+		// every quantity but the extent counts something, so it takes integer values
+		discrete := name != "extent"
+		histograms[name] = LogHistogram(values[name], 10, discrete)
+
+		fit, err := FitPowerLaw(values[name], discrete)
+		if err != nil {
+			// too few avalanches to fit, so leave this quantity out of fits.csv
+			fmt.Printf("%s: %v\n", name, err)
+			continue
+		}
+		fits[name] = fit
+		fmt.Printf("%s: alpha = %.3f +/- %.3f for values >= %g (%d avalanches)\n", name, fit.alpha, fit.stdErr, fit.xmin, fit.n)
+	}
+
+	WriteAvalanchesToFile(avalanches, "avalanches.csv")
+	WriteHistogramsToFile(histograms, names, "histograms.csv")
+	WriteFitsToFile(fits, names, "fits.csv")
+
+	fmt.Println("Avalanche statistics written to avalanches.csv, histograms.csv and fits.csv.")
+}
+
+// RunIdentity parses the CLAs of the identity command, computes the identity element
+// of the sandpile group of a square grid, and draws it to identity.png.
+func RunIdentity() {
+	// CLAs: identity, size of the board, cell width, and optionally a palette
+	if len(os.Args) != 4 && len(os.Args) != 5 {
+		panic("Error: incorrect number of command line arguments for identity.")
+	}
+
+	palette := PaletteByName("gray")
+	if len(os.Args) == 5 {
+		palette = PaletteByName(os.Args[4])
+	}
+
+	size, err := strconv.Atoi(os.Args[2])
+	Check(err)
+
+	cellWidth, err := strconv.Atoi(os.Args[3])
+	Check(err)
+
+	if size <= 0 || cellWidth <= 0 {
+		panic("Error: size and cellWidth must be positive.")
+	}
+
+	start := time.Now()
+	identity := IdentityBoard(size, size)
+	fmt.Printf("Computing the identity of the %d x %d sandpile group took %s\n", size, size, time.Since(start))
+
+	SaveImageToPNG(identity.DrawToImageWithPalette(cellWidth, palette), "identity.png")
+
+	fmt.Println("Identity drawn to identity.png.")
+}
+
+// ParsePlacement takes a placement from the command line: "central", "random", or "random:SEED" to repeat an
+// earlier random run. It returns the placement and the seed of its random numbers, which comes from the clock
+// if none is given, as in rng.ParseSeed. The seed of a random placement is printed so that the run can be repeated.
+func ParsePlacement(s string) (string, int64) {
+	placement, seedString, hasSeed := strings.Cut(s, ":")
+	if !hasSeed {
+		seedString = "time"
+	}
+
+	if placement != "central" && placement != "random" {
+		panic("Error: placement must be central or random.")
+	}
+	if placement == "central" && hasSeed {
+		panic("Error: only a random placement takes a seed.")
+	}
+
+	seed, err := rng.ParseSeed(seedString)
+	Check(err)
+
+	if placement == "random" {
+		fmt.Println("Seed:", seed)
+	}
+
+	return placement, seed
+}
 
+// Check panics if err is not nil.
+func Check(err error) {
+	if err != nil {
+		panic(err)
+	}
 }
@@ -1,12 +1,42 @@
 package main
 
+import (
+	"stencil"
+)
+
 // SimulateSandpilesParallel takes as input a Board object and the number of processors.
 // It returns a slice of Board objects, corresponding to repeated topples of the input
 // board until we reach stability.
+// The board is split into horizontal strips, one per processor. Each processor topples the
+// cells of its own strip, reading the rows just outside its strip to collect the grains
+// that its neighbors send across the boundary.
 func SimulateSandpilesParallel(currentBoard Board, numProcs int) []Board {
 	finalBoards := make([]Board, 0)
+	finalBoards = append(finalBoards, currentBoard)
+
+	e := stencil.NewEngine(currentBoard, stencil.Fixed, numProcs)
 
-	// your code goes here
+	for !Board(e.Current()).IsConverged() {
+		e.Step(ToppleRule)
+		finalBoards = append(finalBoards, e.Snapshot())
+	}
 
 	return finalBoards
 }
+
+// ToppleRule is a stencil rule that computes the number of grains in a cell after a single topple of the
+// whole board: the cell loses four grains if it is unstable and gains one grain from each unstable neighbor.
+func ToppleRule(src *stencil.Reader[int], row, col int) int {
+	val, _ := src.At(row, col)
+	if val >= 4 {
+		val -= 4
+	}
+
+	for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		if neighbor, ok := src.At(row+d[0], col+d[1]); ok && neighbor >= 4 {
+			val++
+		}
+	}
+
+	return val
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestParallelMatchesSerial tests that SimulateSandpilesParallel topples exactly as SimulateSandpiles does, step by
+// step, for several numbers of processors including more processors than rows.
+func TestParallelMatchesSerial(t *testing.T) {
+	boards := map[string]Board{
+		"central": InitializeCentralPile(15, 1000),
+		"random":  InitializeRandomPiles(12, 2000, 2),
+	}
+
+	for name, b := range boards {
+		serial := SimulateSandpiles(b)
+
+		for _, numProcs := range []int{1, 2, 3, 7, 40} {
+			parallel := SimulateSandpilesParallel(b, numProcs)
+			if len(parallel) != len(serial) {
+				t.Errorf("%s, %d processors: %d steps, want %d", name, numProcs, len(parallel)-1, len(serial)-1)
+				continue
+			}
+
+			for i := range serial {
+				if !parallel[i].Equals(serial[i]) {
+					t.Errorf("%s, %d processors: boards differ after %d steps", name, numProcs, i)
+					break
+				}
+			}
+
+			if !parallel[len(parallel)-1].IsConverged() {
+				t.Errorf("%s, %d processors: final board is not stable", name, numProcs)
+			}
+		}
+	}
+}
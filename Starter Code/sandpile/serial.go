@@ -5,8 +5,34 @@ package main
 // board until we reach stability.
 func SimulateSandpiles(currentBoard Board) []Board {
 	finalBoards := make([]Board, 0)
+	finalBoards = append(finalBoards, currentBoard)
 
-	//your code goes here
+	for !currentBoard.IsConverged() {
+		currentBoard = currentBoard.Topple()
+		finalBoards = append(finalBoards, currentBoard)
+	}
 
 	return finalBoards
 }
+
+// Topple is a Board method that returns a new Board resulting from toppling every cell of the
+// Board that has at least four grains at once. A toppling cell loses four grains and gives one
+// grain to each of its four neighbors; grains sent off the edge of the Board are lost.
+func (b Board) Topple() Board {
+	newBoard := b.CopyBoard()
+
+	for i := range b {
+		for j := range b[i] {
+			if b[i][j] >= 4 {
+				newBoard[i][j] -= 4
+				for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+					if b.InField(i+d[0], j+d[1]) {
+						newBoard[i+d[0]][j+d[1]]++
+					}
+				}
+			}
+		}
+	}
+
+	return newBoard
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Bin is one bin of a histogram, covering values in [low, high).
+type Bin struct {
+	low, high float64
+	count     int
+	density   float64 // count divided by the total number of values and by the width of the bin
+}
+
+// PowerLawFit holds the maximum-likelihood fit of a power law p(x) ~ x^(-alpha) to the values at or above xmin.
+type PowerLawFit struct {
+	alpha  float64 // fitted exponent
+	stdErr float64 // standard error of alpha
+	xmin   float64 // smallest value included in the fit
+	n      int     // number of values included in the fit
+	ks     float64 // Kolmogorov-Smirnov distance between the fitted and observed distributions
+}
+
+// LogHistogram takes a slice of values, a number of bins per decade, and a boolean indicating whether the values are integers.
+// It returns a histogram of the positive values using logarithmically spaced bins,
+// starting at the smallest positive value. Nonpositive values (for example, grains that
+// caused no topples) are left out. For integer values, the width of a bin is the number of
+// integers it contains, so that narrow bins without any integers do not distort the density.
+func LogHistogram(values []float64, binsPerDecade int, discrete bool) []Bin {
+	positive := PositiveValues(values)
+	if len(positive) == 0 {
+		return []Bin{}
+	}
+
+	sort.Float64s(positive)
+	minVal := positive[0]
+	maxVal := positive[len(positive)-1]
+
+	ratio := math.Pow(10, 1/float64(binsPerDecade))
+	numBins := int(math.Floor(math.Log(maxVal/minVal)/math.Log(ratio))) + 1
+
+	bins := make([]Bin, numBins)
+	for i := range bins {
+		bins[i].low = minVal * math.Pow(ratio, float64(i))
+		bins[i].high = minVal * math.Pow(ratio, float64(i+1))
+	}
+
+	for _, val := range positive {
+		i := int(math.Floor(math.Log(val/minVal) / math.Log(ratio)))
+		// guard against rounding at the edges of the bins
+		if i < 0 {
+			i = 0
+		}
+		if i >= numBins {
+			i = numBins - 1
+		}
+		bins[i].count++
+	}
+
+	for i := range bins {
+		width := bins[i].high - bins[i].low
+		if discrete {
+			width = math.Ceil(bins[i].high) - math.Ceil(bins[i].low)
+		}
+		if width > 0 {
+			bins[i].density = float64(bins[i].count) / (float64(len(positive)) * width)
+		}
+	}
+
+	return bins
+}
+
+// FitPowerLaw takes a slice of values and a boolean indicating whether the values are integers.
+// It returns the maximum-likelihood power law fit to the positive values, choosing xmin among the
+// smallest distinct values as the one that minimizes the Kolmogorov-Smirnov distance between the fit
+// and the data (Clauset, Shalizi and Newman, 2009). Integer values use the standard continuous
+// approximation with xmin shifted down by one half. It returns an error if no tail of at least
+// 10 values, not all equal, is available to fit.
+func FitPowerLaw(values []float64, discrete bool) (PowerLawFit, error) {
+	positive := PositiveValues(values)
+	sort.Float64s(positive)
+
+	var best PowerLawFit
+	best.ks = math.Inf(1)
+
+	// only try the first few distinct values as xmin, and keep enough values in the tail to fit
+	maxCandidates := 50
+	minTail := 10
+	numCandidates := 0
+
+	for start := 0; start < len(positive) && numCandidates < maxCandidates; start++ {
+		if start > 0 && positive[start] == positive[start-1] {
+			continue
+		}
+		if len(positive)-start < minTail {
+			break
+		}
+		numCandidates++
+
+		fit := FitPowerLawTail(positive[start:], positive[start], discrete)
+		if fit.ks < best.ks {
+			best = fit
+		}
+	}
+
+	if best.n == 0 {
+		return best, fmt.Errorf("cannot fit a power law to %d positive values: need at least %d values that are not all equal", len(positive), minTail)
+	}
+
+	return best, nil
+}
+
+// FitPowerLawTail takes a sorted slice of values, all at least xmin, along with xmin and whether the values are integers.
+// It returns the maximum-likelihood power law fit to the values with that xmin, along with its Kolmogorov-Smirnov distance.
+func FitPowerLawTail(tail []float64, xmin float64, discrete bool) PowerLawFit {
+	var fit PowerLawFit
+
+	fit.xmin = xmin
+	fit.n = len(tail)
+
+	shift := xmin
+	if discrete {
+		shift = xmin - 0.5
+	}
+
+	sumLogs := 0.0
+	for _, val := range tail {
+		sumLogs += math.Log(val / shift)
+	}
+
+	if sumLogs == 0 {
+		// every value equals xmin, so there is no tail to speak of
+		fit.alpha = math.Inf(1)
+		fit.ks = math.Inf(1)
+		return fit
+	}
+
+	n := float64(fit.n)
+	fit.alpha = 1 + n/sumLogs
+	fit.stdErr = (fit.alpha - 1) / math.Sqrt(n)
+
+	// compare the empirical CDF of the sorted tail with the fitted CDF 1 - (x / shift)^(1 - alpha)
+	for k, val := range tail {
+		if discrete {
+			// integers tie often, so compare P(X <= val) once, at the last copy of each value
+			if k+1 < len(tail) && tail[k+1] == val {
+				continue
+			}
+			model := 1 - math.Pow((val+0.5)/shift, 1-fit.alpha)
+			fit.ks = math.Max(fit.ks, math.Abs(model-float64(k+1)/n))
+		} else {
+			model := 1 - math.Pow(val/shift, 1-fit.alpha)
+			below := float64(k) / n
+			above := float64(k+1) / n
+			fit.ks = math.Max(fit.ks, math.Max(math.Abs(model-below), math.Abs(model-above)))
+		}
+	}
+
+	return fit
+}
+
+// PositiveValues takes a slice of values and returns a new slice containing only the positive ones.
+func PositiveValues(values []float64) []float64 {
+	positive := make([]float64, 0, len(values))
+	for _, val := range values {
+		if val > 0 {
+			positive = append(positive, val)
+		}
+	}
+	return positive
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestFitPowerLaw tests that FitPowerLaw recovers the exponent of evenly spread quantiles of a power law.
+func TestFitPowerLaw(t *testing.T) {
+	alpha := 2.5
+	values := make([]float64, 2000)
+	for i := range values {
+		u := (float64(i) + 0.5) / float64(len(values))
+		values[i] = math.Pow(1-u, -1/(alpha-1))
+	}
+
+	fit, err := FitPowerLaw(values, false)
+	if err != nil {
+		t.Fatalf("FitPowerLaw returned %v", err)
+	}
+	if math.Abs(fit.alpha-alpha) > 3*fit.stdErr {
+		t.Errorf("fitted alpha = %g +/- %g, want %g", fit.alpha, fit.stdErr, alpha)
+	}
+}
+
+// TestFitPowerLawTooFewValues tests that FitPowerLaw reports an error rather than a fit when there is too little to fit.
+func TestFitPowerLawTooFewValues(t *testing.T) {
+	tests := map[string][]float64{
+		"empty":     {},
+		"nine":      {1, 2, 3, 4, 5, 6, 7, 8, 9},
+		"zeros":     {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 2},
+		"all equal": {3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3},
+	}
+
+	for name, values := range tests {
+		if fit, err := FitPowerLaw(values, false); err == nil {
+			t.Errorf("%s: FitPowerLaw returned %+v and no error", name, fit)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package stencil provides a parallel engine for two-dimensional automata in which
+// every cell of a grid is updated from its own value and the values of nearby cells.
+//
+// The engine keeps two buffers of the same size: the current generation, which is only
+// read during a step, and the next generation, which is only written. The rows of the
+// grid are split into contiguous blocks, one per goroutine. A worker may read any row
+// of the current buffer, including the halo rows just above and below its block that
+// belong to its neighbors, but it only writes the rows of its own block in the next
+// buffer. Once every worker is done, the two buffers are swapped, so no board is
+// reallocated between generations.
+package stencil
+
+import "workpool"
+
+// Boundary determines how cells outside the grid are treated.
+type Boundary int
+
+const (
+	// Fixed boundaries have no cells outside the grid. Reading outside the grid
+	// reports that the cell is missing.
+	Fixed Boundary = iota
+	// Periodic boundaries wrap around, so that the grid is a torus.
+	Periodic
+)
+
+// Rule computes the value of the cell at (row, col) in the next generation,
+// reading the current generation through src.
+type Rule[T any] func(src *Reader[T], row, col int) T
+
+// Reader gives read-only access to the current generation of an Engine,
+// resolving coordinates outside the grid according to the Engine's boundary.
+type Reader[T any] struct {
+	cells      [][]T
+	rows, cols int
+	boundary   Boundary
+}
+
+// At returns the value of the cell at (row, col) along with true.
+// If (row, col) lies outside the grid and the boundary is Fixed, it returns
+// the zero value of T along with false. If the boundary is Periodic, the
+// coordinates are wrapped around the grid.
+func (r *Reader[T]) At(row, col int) (T, bool) {
+	if row >= 0 && row < r.rows && col >= 0 && col < r.cols {
+		return r.cells[row][col], true
+	}
+
+	if r.boundary == Periodic {
+		return r.cells[Wrap(row, r.rows)][Wrap(col, r.cols)], true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// Rows returns the number of rows in the grid.
+func (r *Reader[T]) Rows() int {
+	return r.rows
+}
+
+// Cols returns the number of columns in the grid.
+func (r *Reader[T]) Cols() int {
+	return r.cols
+}
+
+// Engine advances a grid of cells of type T one generation at a time, dividing
+// the rows of the grid over numProcs goroutines.
+type Engine[T any] struct {
+	current, next [][]T
+	rows, cols    int
+	boundary      Boundary
+	numProcs      int
+}
+
+// NewEngine takes an initial grid, a boundary condition, and a number of processors.
+// It returns a pointer to an Engine whose current generation is a copy of initial.
+// The initial grid must be rectangular and non-empty.
+func NewEngine[T any](initial [][]T, boundary Boundary, numProcs int) *Engine[T] {
+	if len(initial) == 0 || len(initial[0]) == 0 {
+		panic("Error: empty grid given to NewEngine.")
+	}
+	if numProcs < 1 {
+		panic("Error: nonpositive number of processors given to NewEngine.")
+	}
+
+	var e Engine[T]
+
+	e.rows = len(initial)
+	e.cols = len(initial[0])
+	e.boundary = boundary
+
+	// never use more workers than there are rows to hand out
+	e.numProcs = numProcs
+	if e.numProcs > e.rows {
+		e.numProcs = e.rows
+	}
+
+	e.current = makeGrid[T](e.rows, e.cols)
+	e.next = makeGrid[T](e.rows, e.cols)
+
+	for i := range initial {
+		if len(initial[i]) != e.cols {
+			panic("Error: grid given to NewEngine is not rectangular.")
+		}
+		copy(e.current[i], initial[i])
+	}
+
+	return &e
+}
+
+// Step advances the Engine by a single generation, computing every cell of the next
+// generation by applying rule to the current generation.
+func (e *Engine[T]) Step(rule Rule[T]) {
+	src := &Reader[T]{cells: e.current, rows: e.rows, cols: e.cols, boundary: e.boundary}
+
+	// Do returns once every block of rows has been written
+	workpool.Do(e.numProcs, func(i int) {
+		startRow, endRow := RowBlock(e.rows, e.numProcs, i)
+		e.stepRows(rule, src, startRow, endRow)
+	})
+
+	e.current, e.next = e.next, e.current
+}
+
+// stepRows writes rows startRow up to but not including endRow of the next generation.
+func (e *Engine[T]) stepRows(rule Rule[T], src *Reader[T], startRow, endRow int) {
+	for r := startRow; r < endRow; r++ {
+		row := e.next[r]
+		for c := range row {
+			row[c] = rule(src, r, c)
+		}
+	}
+}
+
+// Run advances the Engine by numGens generations using rule.
+func (e *Engine[T]) Run(numGens int, rule Rule[T]) {
+	for i := 0; i < numGens; i++ {
+		e.Step(rule)
+	}
+}
+
+// Current returns the current generation of the Engine. The returned grid is
+// owned by the Engine and will be overwritten by later calls to Step.
+func (e *Engine[T]) Current() [][]T {
+	return e.current
+}
+
+// Snapshot returns a deep copy of the current generation of the Engine.
+func (e *Engine[T]) Snapshot() [][]T {
+	grid := makeGrid[T](e.rows, e.cols)
+	for i := range grid {
+		copy(grid[i], e.current[i])
+	}
+	return grid
+}
+
+// RowBlock takes a number of rows, a number of workers, and the index of a worker.
+// It returns the first row and one past the last row assigned to that worker.
+// Rows are split as evenly as possible, with the first numRows % numProcs workers
+// each receiving one extra row.
+func RowBlock(numRows, numProcs, i int) (int, int) {
+	chunkSize := numRows / numProcs
+	remainder := numRows % numProcs
+
+	startRow := i*chunkSize + min(i, remainder)
+	endRow := startRow + chunkSize
+	if i < remainder {
+		endRow++
+	}
+
+	return startRow, endRow
+}
+
+// Wrap takes an index and a length n and returns the index wrapped into the range [0, n).
+func Wrap(i, n int) int {
+	i %= n
+	if i < 0 {
+		i += n
+	}
+	return i
+}
+
+// makeGrid returns a rows x cols grid of zero values.
+func makeGrid[T any](rows, cols int) [][]T {
+	grid := make([][]T, rows)
+	for i := range grid {
+		grid[i] = make([]T, cols)
+	}
+	return grid
+}
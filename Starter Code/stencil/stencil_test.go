@@ -0,0 +1,102 @@
+package stencil
+
+import (
+	"fmt"
+	"testing"
+)
+
+// sumNeighbors is a rule that replaces each cell by the sum of itself and its four
+// orthogonal neighbors, modulo a small prime so that values stay bounded.
+func sumNeighbors(src *Reader[int], row, col int) int {
+	s := 0
+	for _, d := range [5][2]int{{0, 0}, {-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		if v, ok := src.At(row+d[0], col+d[1]); ok {
+			s += v
+		}
+	}
+	return s % 101
+}
+
+// numberedGrid returns a rows x cols grid whose cells hold distinct small integers.
+func numberedGrid(rows, cols int) [][]int {
+	grid := makeGrid[int](rows, cols)
+	for i := range grid {
+		for j := range grid[i] {
+			grid[i][j] = (i*cols + j) % 7
+		}
+	}
+	return grid
+}
+
+// TestParallelMatchesSerial checks that splitting the rows over several goroutines
+// gives the same result as a single goroutine, for both boundary conditions.
+func TestParallelMatchesSerial(t *testing.T) {
+	for _, boundary := range []Boundary{Fixed, Periodic} {
+		serial := NewEngine(numberedGrid(37, 23), boundary, 1)
+		serial.Run(20, sumNeighbors)
+
+		for _, numProcs := range []int{2, 3, 8, 100} {
+			parallel := NewEngine(numberedGrid(37, 23), boundary, numProcs)
+			parallel.Run(20, sumNeighbors)
+
+			for i, row := range serial.Current() {
+				for j, val := range row {
+					if parallel.Current()[i][j] != val {
+						t.Fatalf("boundary %d, %d procs: cell (%d, %d) = %d, want %d", boundary, numProcs, i, j, parallel.Current()[i][j], val)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestBoundaries checks how each boundary condition resolves cells outside the grid.
+func TestBoundaries(t *testing.T) {
+	grid := numberedGrid(4, 5)
+
+	fixed := NewEngine(grid, Fixed, 1)
+	src := &Reader[int]{cells: fixed.current, rows: 4, cols: 5, boundary: Fixed}
+	if _, ok := src.At(-1, 0); ok {
+		t.Errorf("fixed boundary reported a cell at (-1, 0)")
+	}
+
+	periodic := NewEngine(grid, Periodic, 1)
+	src = &Reader[int]{cells: periodic.current, rows: 4, cols: 5, boundary: Periodic}
+	if v, ok := src.At(-1, 5); !ok || v != grid[3][0] {
+		t.Errorf("periodic boundary At(-1, 5) = %d, %v, want %d, true", v, ok, grid[3][0])
+	}
+}
+
+// TestRowBlock checks that row blocks cover every row exactly once.
+func TestRowBlock(t *testing.T) {
+	for numRows := 1; numRows < 30; numRows++ {
+		for numProcs := 1; numProcs <= numRows; numProcs++ {
+			next := 0
+			for i := 0; i < numProcs; i++ {
+				start, end := RowBlock(numRows, numProcs, i)
+				if start != next || end <= start {
+					t.Fatalf("RowBlock(%d, %d, %d) = %d, %d", numRows, numProcs, i, start, end)
+				}
+				next = end
+			}
+			if next != numRows {
+				t.Fatalf("RowBlock(%d, %d, ...) covers %d rows", numRows, numProcs, next)
+			}
+		}
+	}
+}
+
+// BenchmarkStep measures a single generation on a 1000 x 1000 grid for increasing numbers of processors.
+func BenchmarkStep(b *testing.B) {
+	grid := numberedGrid(1000, 1000)
+
+	for _, numProcs := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("procs=%d", numProcs), func(b *testing.B) {
+			e := NewEngine(grid, Periodic, numProcs)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				e.Step(sumNeighbors)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// Package workpool provides the patterns for dividing work over goroutines that the simulations share.
+//
+// Most parallel code here splits n items into numProcs chunks of about the same size, with the last chunk
+// taking any remainder, runs one goroutine per chunk, and waits for all of them on a channel. For and Chunk
+// do exactly that, Map and Reduce build on them, and Do runs a fixed number of tasks side by side.
+// These never fail; a worker that panics takes the program down as any other panic would.
+//
+// For work whose pieces take very different amounts of time, or that can fail, a Pool runs one task per item
+// on a bounded number of goroutines. The first task to return an error cancels the Pool's context, so that the
+// tasks still waiting are skipped and running tasks that check the context can stop early, and Wait reports
+// that first error. A task that panics is reported as an error too.
+package workpool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Workers takes a number of items n and a number of processors, and returns how many workers For uses:
+// numProcs, but no more than n and at least 1.
+func Workers(n, numProcs int) int {
+	if numProcs > n {
+		numProcs = n
+	}
+	if numProcs < 1 {
+		numProcs = 1
+	}
+	return numProcs
+}
+
+// Chunk takes a number of items n, a number of processors, and the index of a worker i, and returns the start and
+// end of worker i's chunk of the items. Every worker gets n / numProcs items, and the last one also gets the remainder.
+func Chunk(n, numProcs, i int) (int, int) {
+	chunkSize := n / numProcs
+	startIndex := i * chunkSize
+	endIndex := startIndex + chunkSize
+
+	if i == numProcs-1 {
+		// the final chunk extends all the way to the end
+		endIndex = n
+	}
+
+	return startIndex, endIndex
+}
+
+// Do takes a number of tasks and a function, calls task(i) for every i from 0 up to numTasks in its own goroutine,
+// and returns once all of them have finished.
+func Do(numTasks int, task func(i int)) {
+	finished := make(chan bool, numTasks)
+
+	for i := 0; i < numTasks; i++ {
+		go func(i int) {
+			task(i)
+			finished <- true
+		}(i)
+	}
+
+	for i := 0; i < numTasks; i++ {
+		<-finished
+	}
+}
+
+// For takes a number of items n, a number of processors, and a function that works on the items from start up to
+// but not including end. It splits the items into Workers(n, numProcs) chunks with Chunk and calls work on each chunk
+// in its own goroutine, along with the index of the chunk, returning once they have all finished.
+// The index lets every chunk use its own random stream or its own slot for a result.
+func For(n, numProcs int, work func(worker, start, end int)) {
+	numProcs = Workers(n, numProcs)
+
+	Do(numProcs, func(i int) {
+		start, end := Chunk(n, numProcs, i)
+		work(i, start, end)
+	})
+}
+
+// Map takes a slice of items, a number of processors, and a function, and returns the slice of f applied to every item,
+// dividing the items over numProcs workers as For does.
+func Map[T, U any](items []T, numProcs int, f func(T) U) []U {
+	results := make([]U, len(items))
+
+	For(len(items), numProcs, func(worker, start, end int) {
+		for i := start; i < end; i++ {
+			results[i] = f(items[i])
+		}
+	})
+
+	return results
+}
+
+// Reduce takes a number of items n, a number of processors, a function that computes the result of the items from start
+// up to but not including end, and a function that combines two results. It computes the result of every chunk of For
+// in parallel and returns them combined from the first chunk to the last, so that the answer is the same every time
+// even if combine is not commutative, as with floating-point sums.
+func Reduce[T any](n, numProcs int, chunk func(worker, start, end int) T, combine func(T, T) T) T {
+	results := make([]T, Workers(n, numProcs))
+
+	For(n, numProcs, func(worker, start, end int) {
+		results[worker] = chunk(worker, start, end)
+	})
+
+	total := results[0]
+	for _, r := range results[1:] {
+		total = combine(total, r)
+	}
+	return total
+}
+
+// Pool runs tasks on at most a fixed number of goroutines at once, stopping at the first error.
+// A Pool is used once: start tasks with Go, then call Wait.
+type Pool struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	slots  chan bool // holds a value for every running task
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+}
+
+// NewPool takes a context and a size, and returns a Pool that runs at most size tasks at a time.
+// The Pool's tasks are cancelled when ctx is, or when one of them fails.
+func NewPool(ctx context.Context, size int) *Pool {
+	if size < 1 {
+		size = 1
+	}
+
+	var p Pool
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	p.slots = make(chan bool, size)
+	return &p
+}
+
+// Context is a Pool method that returns the context passed to its tasks, which is cancelled once a task fails.
+func (p *Pool) Context() context.Context {
+	return p.ctx
+}
+
+// fail is a Pool method that records err if it is the first error and cancels the remaining tasks.
+func (p *Pool) fail(err error) {
+	p.once.Do(func() {
+		p.err = err
+	})
+	p.cancel()
+}
+
+// Go is a Pool method that takes a task and runs it on a new goroutine once fewer than size tasks are running,
+// blocking until then. If the Pool has been cancelled by then, the task is skipped.
+func (p *Pool) Go(task func(ctx context.Context) error) {
+	select {
+	case p.slots <- true:
+	case <-p.ctx.Done():
+		p.fail(p.ctx.Err())
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				p.fail(fmt.Errorf("task panicked: %v", r))
+			}
+			<-p.slots
+			p.wg.Done()
+		}()
+
+		if p.ctx.Err() != nil {
+			p.fail(p.ctx.Err())
+			return
+		}
+		if err := task(p.ctx); err != nil {
+			p.fail(err)
+		}
+	}()
+}
+
+// Wait is a Pool method that waits for every task started with Go to finish, and returns the first error
+// any of them returned, or the error of the context if it was cancelled, or nil.
+func (p *Pool) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+	return p.err
+}
+
+// ForEach takes a context, a number of items n, a number of processors, and a task, and calls task(ctx, i) for every
+// i from 0 up to n on a Pool of numProcs goroutines, handing out items in order as goroutines become free.
+// It returns the first error, after which no more items are started.
+func ForEach(ctx context.Context, n, numProcs int, task func(ctx context.Context, i int) error) error {
+	p := NewPool(ctx, numProcs)
+
+	for i := 0; i < n; i++ {
+		i := i
+		p.Go(func(ctx context.Context) error {
+			return task(ctx, i)
+		})
+	}
+
+	return p.Wait()
+}
@@ -0,0 +1,153 @@
+package workpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+// TestChunk checks that the chunks cover every item exactly once, with the remainder in the last chunk.
+func TestChunk(t *testing.T) {
+	for n := 0; n < 30; n++ {
+		for numProcs := 1; numProcs <= 8; numProcs++ {
+			next := 0
+			for i := 0; i < numProcs; i++ {
+				start, end := Chunk(n, numProcs, i)
+				if start != next || end < start {
+					t.Fatalf("Chunk(%d, %d, %d) = %d, %d", n, numProcs, i, start, end)
+				}
+				if i < numProcs-1 && end-start != n/numProcs {
+					t.Fatalf("Chunk(%d, %d, %d) has %d items, want %d", n, numProcs, i, end-start, n/numProcs)
+				}
+				next = end
+			}
+			if next != n {
+				t.Fatalf("Chunk(%d, %d, ...) covers %d items", n, numProcs, next)
+			}
+		}
+	}
+}
+
+// TestForVisitsEveryItem checks that For calls work on every item exactly once, even with more processors than items.
+func TestForVisitsEveryItem(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 100} {
+		visits := make([]int32, n)
+		For(n, 8, func(worker, start, end int) {
+			for i := start; i < end; i++ {
+				atomic.AddInt32(&visits[i], 1)
+			}
+		})
+		for i, v := range visits {
+			if v != 1 {
+				t.Fatalf("item %d of %d visited %d times", i, n, v)
+			}
+		}
+	}
+}
+
+// TestMapReduce checks Map and Reduce against serial computations.
+func TestMapReduce(t *testing.T) {
+	items := make([]int, 1001)
+	for i := range items {
+		items[i] = i
+	}
+
+	squares := Map(items, 4, func(x int) int { return x * x })
+	for i := range items {
+		if squares[i] != i*i {
+			t.Fatalf("Map gave %d at index %d, want %d", squares[i], i, i*i)
+		}
+	}
+
+	sum := Reduce(len(items), 4, func(worker, start, end int) int {
+		s := 0
+		for i := start; i < end; i++ {
+			s += items[i]
+		}
+		return s
+	}, func(x, y int) int { return x + y })
+
+	if sum != 1000*1001/2 {
+		t.Errorf("Reduce gave sum %d, want %d", sum, 1000*1001/2)
+	}
+}
+
+// TestPoolBounded checks that a Pool never runs more than its size of tasks at once.
+func TestPoolBounded(t *testing.T) {
+	var running, maxRunning int32
+	block := make(chan bool)
+
+	p := NewPool(context.Background(), 3)
+	go func() {
+		for i := 0; i < 6; i++ {
+			block <- true
+		}
+	}()
+	for i := 0; i < 6; i++ {
+		p.Go(func(ctx context.Context) error {
+			now := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&maxRunning)
+				if now <= old || atomic.CompareAndSwapInt32(&maxRunning, old, now) {
+					break
+				}
+			}
+			<-block
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Wait returned %v", err)
+	}
+	if maxRunning > 3 {
+		t.Errorf("%d tasks ran at once in a pool of size 3", maxRunning)
+	}
+}
+
+// TestForEachError checks that the first error is returned and stops the remaining items.
+func TestForEachError(t *testing.T) {
+	failure := errors.New("item 5 failed")
+	var started int32
+
+	err := ForEach(context.Background(), 1000, 1, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&started, 1)
+		if i == 5 {
+			return failure
+		}
+		return nil
+	})
+
+	if err != failure {
+		t.Errorf("ForEach returned %v, want %v", err, failure)
+	}
+	// with one goroutine, items are started in order, so at most one more may slip in after the failure
+	if started > 7 {
+		t.Errorf("%d items started after a failure at item 5", started)
+	}
+}
+
+// TestForEachCancelledAndPanic checks that a cancelled context and a panicking task are both reported as errors.
+func TestForEachCancelledAndPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := ForEach(ctx, 10, 2, func(ctx context.Context, i int) error {
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForEach with a cancelled context returned %v", err)
+	}
+
+	err = ForEach(context.Background(), 10, 2, func(ctx context.Context, i int) error {
+		if i == 3 {
+			panic("bad item")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Errorf("ForEach with a panicking task returned no error")
+	}
+}